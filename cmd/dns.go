@@ -2,7 +2,14 @@ package cmd
 
 import (
 	"errors"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
 
+	"github.com/Method-Security/osintscan/configs"
 	"github.com/Method-Security/osintscan/internal/dns"
 	"github.com/Method-Security/osintscan/utils"
 	"github.com/spf13/cobra"
@@ -47,7 +54,32 @@ func (a *OsintScan) InitDNSCommand() {
 				a.OutputSignal.AddError(err)
 				return
 			}
-			report, err := dns.GetDomainDNSRecords(cmd.Context(), domain)
+			dkimSelectors, err := cmd.Flags().GetStringSlice("dkim-selectors")
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+			dkimSelectorFiles, err := cmd.Flags().GetStringSlice("dkim-selectors-file")
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+			fileDkimSelectors, err := utils.GetEntriesFromFiles(dkimSelectorFiles)
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+			dkimThreads, err := cmd.Flags().GetInt("dkim-threads")
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+			resolver, err := resolverFromFlags(cmd)
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+			report, err := dns.GetDomainDNSRecords(cmd.Context(), domain, resolver, slices.Concat(configs.DkimSelectors(), dkimSelectors, fileDkimSelectors), dkimThreads)
 			if err != nil {
 				a.OutputSignal.AddError(err)
 				return
@@ -57,6 +89,10 @@ func (a *OsintScan) InitDNSCommand() {
 	}
 
 	recordCmd.Flags().String("domain", "", "Domain to get DNS records for")
+	recordCmd.Flags().StringSlice("dkim-selectors", []string{}, "DKIM selectors to check in addition to the bundled selectors")
+	recordCmd.Flags().StringSlice("dkim-selectors-file", []string{}, "Paths to files containing DKIM selectors to check in addition to the bundled selectors")
+	recordCmd.Flags().Int("dkim-threads", 10, "Number of parallel DKIM selector queries")
+	addResolverFlags(recordCmd)
 
 	subenumCmd := &cobra.Command{
 		Use:   "subenum",
@@ -67,14 +103,75 @@ func (a *OsintScan) InitDNSCommand() {
 	subenumpassiveCmd := &cobra.Command{
 		Use:   "passive",
 		Short: "Passively enumerate subdomains for a given domain",
-		Long:  `Passively enumerate subdomains for a given domain`,
+		Long: `
+Passively enumerate subdomains for a given domain by querying the subfinder sources. The sources that reported each subdomain are listed in sources. Names that are not valid subdomains of the domain are dropped.
+
+Sources that need API keys, such as securitytrails, virustotal, censys and chaos, only run when they are given keys. Keys are read from the subfinder provider config file, and from <SOURCE>_API_KEY environment variables holding comma separated keys, which take precedence.
+
+With --resolve, every subdomain is resolved and listed in resolutions with its addresses, CNAME chain and status: LIVE when it resolves to addresses, WILDCARD when its answer is the same as a wildcard of its parent domain, and DEAD otherwise.`,
 		Run: func(cmd *cobra.Command, args []string) {
 			domain, err := cmd.Flags().GetString("domain")
 			if err != nil {
 				a.OutputSignal.AddError(err)
 				return
 			}
-			report, err := dns.GetDomainSubdomainsPassive(cmd.Context(), domain)
+			sources, err := cmd.Flags().GetStringSlice("sources")
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+			excludeSources, err := cmd.Flags().GetStringSlice("exclude-sources")
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+			all, err := cmd.Flags().GetBool("all")
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+			recursive, err := cmd.Flags().GetBool("recursive")
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+			providerConfig, err := cmd.Flags().GetString("provider-config")
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+			resolve, err := cmd.Flags().GetBool("resolve")
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+			parallelThreads, err := cmd.Flags().GetInt("threads")
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+			rateLimit, err := cmd.Flags().GetInt("rate-limit")
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+			resolver, err := resolverFromFlags(cmd)
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+
+			config := dns.PassiveConfig{
+				Sources:        sources,
+				ExcludeSources: excludeSources,
+				All:            all,
+				Recursive:      recursive,
+				ProviderConfig: providerConfig,
+				Resolve:        resolve,
+				Threads:        parallelThreads,
+				RateLimit:      rateLimit,
+			}
+			report, err := dns.GetDomainSubdomainsPassive(cmd.Context(), domain, config, resolver)
 			if err != nil {
 				a.OutputSignal.AddError(err)
 				return
@@ -84,6 +181,15 @@ func (a *OsintScan) InitDNSCommand() {
 	}
 
 	subenumpassiveCmd.Flags().String("domain", "", "Domain to get subdomains for")
+	subenumpassiveCmd.Flags().StringSlice("sources", []string{}, "Sources to query instead of the default sources ("+strings.Join(dns.PassiveSourceNames(), ", ")+")")
+	subenumpassiveCmd.Flags().StringSlice("exclude-sources", []string{}, "Sources not to query")
+	subenumpassiveCmd.Flags().Bool("all", false, "Query every source, including slow ones")
+	subenumpassiveCmd.Flags().Bool("recursive", false, "Only query sources that can enumerate subdomains of subdomains")
+	subenumpassiveCmd.Flags().String("provider-config", "", "Path to a subfinder provider config file holding the API keys of keyed sources (default $HOME/.config/subfinder/provider-config.yaml)")
+	subenumpassiveCmd.Flags().Bool("resolve", false, "Resolve every subdomain found and mark it live, dead or wildcard")
+	subenumpassiveCmd.Flags().Int("threads", 100, "Number of DNS queries in flight at once while resolving")
+	subenumpassiveCmd.Flags().Int("rate-limit", 0, "Maximum queries per second sent to each resolver while resolving (0 for no limit)")
+	addResolverFlags(subenumpassiveCmd)
 
 	subenumCmd.AddCommand(subenumpassiveCmd)
 
@@ -97,7 +203,11 @@ Bruteforce subdomains for a given domain. This tool recursively discovers subdom
 2. If sub.example.com exists, will then check deeper subdomains like deep.sub.example.com
 3. If sub.example.com does not exist, will not check deep.sub.example.com
 
-This ensures efficient scanning but means some valid deep subdomains may be missed if their parent subdomain does not exist.`,
+Names that exist without any address records are reported separately and recursed into like any other valid subdomain. Empty non-terminals, such as corp.example.com when only vpn.corp.example.com has records, are reported in emptyNonTerminals, and names that only hold other record types in noAddressNames.
+
+This ensures efficient scanning but means some valid deep subdomains may be missed if their parent subdomain does not exist.
+
+With --learn-from, the naming conventions of the target are learned from the names in previous passive, certificate or brute force reports. The labels of those names, and the dash separated parts of the labels, are ranked by how many names they appear in and enumerated before the rest of the wordlist.`,
 		Run: func(cmd *cobra.Command, args []string) {
 			domain, err := cmd.Flags().GetString("domain")
 			if err != nil {
@@ -122,7 +232,38 @@ This ensures efficient scanning but means some valid deep subdomains may be miss
 				a.OutputSignal.AddError(err)
 				return
 			}
-			allSubdomains := append(subdomains, fileSubdomains...)
+			wordlist, err := cmd.Flags().GetString("wordlist")
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+			var wordlistSubdomains []string
+			if wordlist != "" {
+				wordlistSubdomains, err = configs.Wordlist(wordlist)
+				if err != nil {
+					a.OutputSignal.AddError(err)
+					return
+				}
+			}
+			allSubdomains := slices.Concat(subdomains, fileSubdomains, wordlistSubdomains)
+
+			learnFrom, err := cmd.Flags().GetStringSlice("learn-from")
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+			learnExport, err := cmd.Flags().GetString("learn-export")
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+			if len(learnFrom) > 0 {
+				allSubdomains, err = dns.LearnWordlist(domain, learnFrom, allSubdomains, learnExport)
+				if err != nil {
+					a.OutputSignal.AddError(err)
+					return
+				}
+			}
 			if len(allSubdomains) == 0 {
 				a.OutputSignal.AddError(errors.New("no subdomains provided"))
 				return
@@ -133,6 +274,11 @@ This ensures efficient scanning but means some valid deep subdomains may be miss
 				a.OutputSignal.AddError(err)
 				return
 			}
+			rateLimit, err := cmd.Flags().GetInt("rate-limit")
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
 			recursiveDepth, err := cmd.Flags().GetInt("maxdepth")
 			if err != nil {
 				a.OutputSignal.AddError(err)
@@ -144,7 +290,44 @@ This ensures efficient scanning but means some valid deep subdomains may be miss
 				return
 			}
 
-			report, err := dns.GetDomainSubdomainsBrute(cmd.Context(), domain, allSubdomains, parallelThreads, recursiveDepth, timeout)
+			fingerprintsPath, err := cmd.Flags().GetString("fingerprints")
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+			checkpointPath, err := cmd.Flags().GetString("checkpoint")
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+			resumePath, err := cmd.Flags().GetString("resume")
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+			var resume *dns.BruteCheckpoint
+			if resumePath != "" {
+				resume, err = dns.LoadBruteCheckpoint(resumePath)
+				if err != nil {
+					a.OutputSignal.AddError(err)
+					return
+				}
+				if checkpointPath == "" {
+					checkpointPath = resumePath
+				}
+			}
+
+			resolver, err := resolverFromFlags(cmd)
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+
+			// Stop on Ctrl-C so that the checkpoint is written and the results so far are reported
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			report, err := dns.GetDomainSubdomainsBrute(ctx, domain, allSubdomains, parallelThreads, rateLimit, recursiveDepth, timeout, fingerprintsPath, checkpointPath, resume, resolver)
 			if err != nil {
 				a.OutputSignal.AddError(err)
 				return
@@ -155,68 +338,178 @@ This ensures efficient scanning but means some valid deep subdomains may be miss
 
 	subenumbruteCmd.Flags().String("domain", "", "Domain to get subdomains for")
 	subenumbruteCmd.Flags().StringSlice("subdomain", []string{}, "List of subdomains to enumerate")
-	subenumbruteCmd.Flags().StringSlice("file", []string{}, "List of files containing subdomains to enumerate")
-	subenumbruteCmd.Flags().Int("threads", 20, "Number of parallel threads")
+	subenumbruteCmd.Flags().StringSlice("file", []string{}, "List of files containing subdomains to enumerate, optionally gzip compressed")
+	subenumbruteCmd.Flags().String("wordlist", "", "Bundled wordlist to enumerate ("+strings.Join(configs.WordlistNames(), ", ")+")")
+	subenumbruteCmd.Flags().StringSlice("learn-from", []string{}, "List of JSON reports of previous subdomain or certificate enumerations to learn a wordlist from, which is enumerated before the other subdomains")
+	subenumbruteCmd.Flags().String("learn-export", "", "Path to write the learned wordlist to, gzip compressed when it ends in .gz")
+	subenumbruteCmd.Flags().Int("threads", 100, "Number of DNS queries in flight at once")
+	subenumbruteCmd.Flags().Int("rate-limit", 0, "Maximum queries per second sent to each resolver (0 for no limit)")
 	subenumbruteCmd.Flags().Int("maxdepth", 3, "Maximum recursion depth")
 	subenumbruteCmd.Flags().Int("timeout", 0, "Maximum time of enumeration (Minutes)")
+	subenumbruteCmd.Flags().String("fingerprints", "", "Path to the takeover fingerprints file dangling CNAMEs are matched against (defaults to the bundled fingerprints)")
+	subenumbruteCmd.Flags().String("checkpoint", "", "Path to a file the progress of the enumeration is periodically written to")
+	subenumbruteCmd.Flags().String("resume", "", "Path to a checkpoint file to resume the enumeration from")
+	addResolverFlags(subenumbruteCmd)
 
 	_ = subenumbruteCmd.MarkFlagRequired("domain")
 
 	subenumCmd.AddCommand(subenumbruteCmd)
 
-	takeoverCmd := &cobra.Command{
-		Use:   "takeover",
-		Short: "Detect domain takeovers given a list of targets",
-		Long:  `Detect domain takeovers given a list of targets`,
+	subenumwalkCmd := &cobra.Command{
+		Use:   "walk",
+		Short: "Enumerate subdomains for a given domain by walking its DNSSEC NSEC chain",
+		Long: `
+Enumerate subdomains for a given domain by walking its DNSSEC NSEC chain. Zones signed with NSEC link every name to the next one, so following the chain lists the whole zone without a wordlist.
+
+Zones signed with NSEC3 only publish hashes of their names. For those zones the hashes are collected instead, and can be written to a file in the hashcat mode 8300 format for offline cracking.`,
 		Run: func(cmd *cobra.Command, args []string) {
-			targets, err := cmd.Flags().GetStringSlice("targets")
+			domain, err := cmd.Flags().GetString("domain")
 			if err != nil {
 				a.OutputSignal.AddError(err)
 				return
 			}
-			filePaths, err := cmd.Flags().GetStringSlice("files")
+			maxQueries, err := cmd.Flags().GetInt("max-queries")
 			if err != nil {
 				a.OutputSignal.AddError(err)
 				return
 			}
-			fileTargets, err := utils.GetEntriesFromFiles(filePaths)
+			hashesFile, err := cmd.Flags().GetString("hashes-file")
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+			resolver, err := resolverFromFlags(cmd)
 			if err != nil {
 				a.OutputSignal.AddError(err)
 				return
 			}
 
-			allTargets := append(targets, fileTargets...)
-
-			if len(allTargets) == 0 {
-				a.OutputSignal.AddError(errors.New("no targets specified"))
+			report, err := dns.GetDomainSubdomainsWalk(cmd.Context(), domain, maxQueries, resolver)
+			if err != nil {
+				a.OutputSignal.AddError(err)
 				return
 			}
+			if hashesFile != "" && report.Nsec3 != nil {
+				if err := dns.WriteNsec3Hashes(hashesFile, report.Nsec3); err != nil {
+					a.OutputSignal.AddError(err)
+					return
+				}
+			}
+			a.OutputSignal.Content = report
+		},
+	}
 
-			fingerprintsPath, err := cmd.Flags().GetString("fingerprints")
+	subenumwalkCmd.Flags().String("domain", "", "Domain to get subdomains for")
+	subenumwalkCmd.Flags().Int("max-queries", 10000, "Maximum number of queries to send while walking the zone")
+	subenumwalkCmd.Flags().String("hashes-file", "", "Path to write collected NSEC3 hashes to in hashcat mode 8300 format")
+	addResolverFlags(subenumwalkCmd)
+
+	_ = subenumwalkCmd.MarkFlagRequired("domain")
+
+	subenumCmd.AddCommand(subenumwalkCmd)
+
+	subenumpermuteCmd := &cobra.Command{
+		Use:   "permute",
+		Short: "Enumerate subdomains for a given domain by permuting known subdomains",
+		Long: `
+Enumerate subdomains for a given domain by permuting known subdomains, such as the output of passive or brute mode. Variants of each known subdomain are generated the way altdns and dnsgen do:
+
+1. Numbers are incremented and decremented, so api2.example.com gives api1.example.com and api3.example.com
+2. Environment words are swapped, so dev.api.example.com gives stg.api.example.com and prod.api.example.com
+3. Tokens are inserted as new labels, replace existing labels, and are appended or prepended with a dash, so api.example.com gives internal.api.example.com, api-internal.example.com and internal-api.example.com
+
+The variants are resolved like brute mode, skipping names that only match a wildcard of their parent domain.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			domain, err := cmd.Flags().GetString("domain")
 			if err != nil {
 				a.OutputSignal.AddError(err)
 				return
 			}
 
-			onlySuccessful, err := cmd.Flags().GetBool("onlysuccessful")
+			subdomains, err := cmd.Flags().GetStringSlice("subdomain")
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+			subdomainFiles, err := cmd.Flags().GetStringSlice("file")
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+			fileSubdomains, err := utils.GetEntriesFromFiles(subdomainFiles)
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+			reportFiles, err := cmd.Flags().GetStringSlice("report")
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+			reportSubdomains, err := dns.ReadSubenumReports(reportFiles)
 			if err != nil {
 				a.OutputSignal.AddError(err)
 				return
 			}
+			allSubdomains := slices.Concat(subdomains, fileSubdomains, reportSubdomains)
+			if len(allSubdomains) == 0 {
+				a.OutputSignal.AddError(errors.New("no known subdomains provided"))
+				return
+			}
 
-			setHTTPS, err := cmd.Flags().GetBool("https")
+			tokens, err := cmd.Flags().GetStringSlice("tokens")
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+			tokenFiles, err := cmd.Flags().GetStringSlice("tokens-file")
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+			fileTokens, err := utils.GetEntriesFromFiles(tokenFiles)
 			if err != nil {
 				a.OutputSignal.AddError(err)
 				return
 			}
+			allTokens := slices.Concat(configs.PermutationTokens(), tokens, fileTokens)
 
+			parallelThreads, err := cmd.Flags().GetInt("threads")
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+			rateLimit, err := cmd.Flags().GetInt("rate-limit")
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+			maxCandidates, err := cmd.Flags().GetInt("max-candidates")
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
 			timeout, err := cmd.Flags().GetInt("timeout")
 			if err != nil {
 				a.OutputSignal.AddError(err)
 				return
 			}
+			fingerprintsPath, err := cmd.Flags().GetString("fingerprints")
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+			resolver, err := resolverFromFlags(cmd)
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+
+			// Stop on Ctrl-C so that the results so far are reported
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
 
-			report, err := dns.DetectDomainTakeover(allTargets, fingerprintsPath, onlySuccessful, setHTTPS, timeout)
+			report, err := dns.GetDomainSubdomainsPermute(ctx, domain, allSubdomains, allTokens, parallelThreads, rateLimit, maxCandidates, timeout, fingerprintsPath, resolver)
 			if err != nil {
 				a.OutputSignal.AddError(err)
 				return
@@ -225,16 +518,584 @@ This ensures efficient scanning but means some valid deep subdomains may be miss
 		},
 	}
 
-	takeoverCmd.Flags().StringSlice("targets", []string{}, "URL targets to analyze")
-	takeoverCmd.Flags().String("fingerprints", "configs/fingerprints.json", "Path to fingerprints file")
-	takeoverCmd.Flags().StringSlice("files", []string{}, "Paths to files containing the list of targets")
-	takeoverCmd.Flags().Bool("onlysuccessful", false, "Only check sites with secure SSL")
-	takeoverCmd.Flags().Bool("https", false, "Only check sites with secure SSL")
-	takeoverCmd.Flags().Int("timeout", 10, "Request timeout in seconds")
+	subenumpermuteCmd.Flags().String("domain", "", "Domain to get subdomains for")
+	subenumpermuteCmd.Flags().StringSlice("subdomain", []string{}, "List of known subdomains to permute")
+	subenumpermuteCmd.Flags().StringSlice("file", []string{}, "List of files containing known subdomains to permute, optionally gzip compressed")
+	subenumpermuteCmd.Flags().StringSlice("report", []string{}, "List of JSON reports of previous subdomain enumerations whose subdomains are permuted")
+	subenumpermuteCmd.Flags().StringSlice("tokens", []string{}, "List of tokens to permute with, in addition to the bundled tokens")
+	subenumpermuteCmd.Flags().StringSlice("tokens-file", []string{}, "List of files containing tokens to permute with, in addition to the bundled tokens")
+	subenumpermuteCmd.Flags().Int("threads", 100, "Number of DNS queries in flight at once")
+	subenumpermuteCmd.Flags().Int("rate-limit", 0, "Maximum queries per second sent to each resolver (0 for no limit)")
+	subenumpermuteCmd.Flags().Int("max-candidates", 1000000, "Maximum number of variants to generate and resolve")
+	subenumpermuteCmd.Flags().Int("timeout", 0, "Maximum time of enumeration (Minutes)")
+	subenumpermuteCmd.Flags().String("fingerprints", "", "Path to the takeover fingerprints file dangling CNAMEs are matched against (defaults to the bundled fingerprints)")
+	addResolverFlags(subenumpermuteCmd)
 
-	a.DNSCmd.AddCommand(recordCmd)
-	a.DNSCmd.AddCommand(certsCmd)
-	a.DNSCmd.AddCommand(subenumCmd)
-	a.DNSCmd.AddCommand(takeoverCmd)
-	a.RootCmd.AddCommand(a.DNSCmd)
+	_ = subenumpermuteCmd.MarkFlagRequired("domain")
+
+	subenumCmd.AddCommand(subenumpermuteCmd)
+
+	subenumallCmd := &cobra.Command{
+		Use:   "all",
+		Short: "Enumerate subdomains for a given domain with every technique",
+		Long: `
+Enumerate subdomains for a given domain with every technique and merge the results into a single report:
+
+1. Passive sources and the certificates logged by crt.sh are queried at the same time
+2. The names they return are resolved and marked live, dead or wildcard in resolutions
+3. Brute force runs with the wordlist, recursing into the passive and certificate names that exist as well as the names it finds
+4. Variants of every name that exists, including the names brute force found, are generated with the permutation tokens and resolved
+
+Variants that exist are not permuted or brute forced in turn. Every name is reported once. The methods that found it are listed in the methods of its entry in subdomainDetails, and the passive sources that reported it in sources.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			domain, err := cmd.Flags().GetString("domain")
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+			sources, err := cmd.Flags().GetStringSlice("sources")
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+			excludeSources, err := cmd.Flags().GetStringSlice("exclude-sources")
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+			allSources, err := cmd.Flags().GetBool("all-sources")
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+			recursive, err := cmd.Flags().GetBool("recursive")
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+			providerConfig, err := cmd.Flags().GetString("provider-config")
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+
+			subdomains, err := cmd.Flags().GetStringSlice("subdomain")
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+			subdomainlistFiles, err := cmd.Flags().GetStringSlice("file")
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+			fileSubdomains, err := utils.GetEntriesFromFiles(subdomainlistFiles)
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+			wordlist, err := cmd.Flags().GetString("wordlist")
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+			var wordlistSubdomains []string
+			if wordlist != "" {
+				wordlistSubdomains, err = configs.Wordlist(wordlist)
+				if err != nil {
+					a.OutputSignal.AddError(err)
+					return
+				}
+			}
+			allSubdomains := slices.Concat(subdomains, fileSubdomains, wordlistSubdomains)
+			tokens, err := cmd.Flags().GetStringSlice("tokens")
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+			tokenFiles, err := cmd.Flags().GetStringSlice("tokens-file")
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+			fileTokens, err := utils.GetEntriesFromFiles(tokenFiles)
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+			allTokens := slices.Concat(configs.PermutationTokens(), tokens, fileTokens)
+
+			parallelThreads, err := cmd.Flags().GetInt("threads")
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+			rateLimit, err := cmd.Flags().GetInt("rate-limit")
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+			recursiveDepth, err := cmd.Flags().GetInt("maxdepth")
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+			maxCandidates, err := cmd.Flags().GetInt("max-candidates")
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+			timeout, err := cmd.Flags().GetInt("timeout")
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+			resolver, err := resolverFromFlags(cmd)
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+
+			config := dns.PassiveConfig{
+				Sources:        sources,
+				ExcludeSources: excludeSources,
+				All:            allSources,
+				Recursive:      recursive,
+				ProviderConfig: providerConfig,
+				Threads:        parallelThreads,
+				RateLimit:      rateLimit,
+			}
+
+			// Stop on Ctrl-C so that the results so far are reported
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			report, err := dns.GetDomainSubdomainsAll(ctx, domain, config, allSubdomains, recursiveDepth, allTokens, maxCandidates, timeout, resolver)
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+			a.OutputSignal.Content = report
+		},
+	}
+
+	subenumallCmd.Flags().String("domain", "", "Domain to get subdomains for")
+	subenumallCmd.Flags().StringSlice("sources", []string{}, "Passive sources to query instead of the default sources")
+	subenumallCmd.Flags().StringSlice("exclude-sources", []string{}, "Passive sources not to query")
+	subenumallCmd.Flags().Bool("all-sources", false, "Query every passive source, including slow ones")
+	subenumallCmd.Flags().Bool("recursive", false, "Only query passive sources that can enumerate subdomains of subdomains")
+	subenumallCmd.Flags().String("provider-config", "", "Path to a subfinder provider config file holding the API keys of keyed sources (default $HOME/.config/subfinder/provider-config.yaml)")
+	subenumallCmd.Flags().StringSlice("subdomain", []string{}, "List of subdomains to brute force")
+	subenumallCmd.Flags().StringSlice("file", []string{}, "List of files containing subdomains to brute force, optionally gzip compressed")
+	subenumallCmd.Flags().String("wordlist", "5000", "Bundled wordlist to brute force ("+strings.Join(configs.WordlistNames(), ", ")+"), or empty for none")
+	subenumallCmd.Flags().Int("threads", 100, "Number of DNS queries in flight at once")
+	subenumallCmd.Flags().Int("rate-limit", 0, "Maximum queries per second sent to each resolver (0 for no limit)")
+	subenumallCmd.Flags().Int("maxdepth", 3, "Maximum brute force recursion depth")
+	subenumallCmd.Flags().StringSlice("tokens", []string{}, "List of tokens to permute with, in addition to the bundled tokens")
+	subenumallCmd.Flags().StringSlice("tokens-file", []string{}, "List of files containing tokens to permute with, in addition to the bundled tokens")
+	subenumallCmd.Flags().Int("max-candidates", 1000000, "Maximum number of variants to generate and resolve (0 to skip permutation)")
+	subenumallCmd.Flags().Int("timeout", 0, "Maximum time of enumeration (Minutes)")
+	addResolverFlags(subenumallCmd)
+
+	_ = subenumallCmd.MarkFlagRequired("domain")
+
+	subenumCmd.AddCommand(subenumallCmd)
+
+	subenumwordlistsCmd := &cobra.Command{
+		Use:   "wordlists",
+		Short: "List the subdomain wordlists bundled with osintscan",
+		Long: `
+List the subdomain wordlists bundled with osintscan, which can be used by brute mode through --wordlist. A bundled wordlist can be exported to a file with --export, for instance to extend it into a custom list.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			exportName, err := cmd.Flags().GetString("export")
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+			exportFile, err := cmd.Flags().GetString("export-file")
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+
+			report, err := dns.GetWordlists(exportName, exportFile)
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+			a.OutputSignal.Content = report
+		},
+	}
+
+	subenumwordlistsCmd.Flags().String("export", "", "Name of a bundled wordlist to export")
+	subenumwordlistsCmd.Flags().String("export-file", "", "Path to export the wordlist to, gzip compressed when it ends in .gz (default wordlist-<name>.txt)")
+
+	subenumCmd.AddCommand(subenumwordlistsCmd)
+
+	takeoverCmd := &cobra.Command{
+		Use:   "takeover",
+		Short: "Detect domain takeovers given a list of targets",
+		Long:  `Detect domain takeovers given a list of targets`,
+		Run: func(cmd *cobra.Command, args []string) {
+			targets, err := cmd.Flags().GetStringSlice("targets")
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+			filePaths, err := cmd.Flags().GetStringSlice("files")
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+			fileTargets, err := utils.GetEntriesFromFiles(filePaths)
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+
+			allTargets := append(targets, fileTargets...)
+
+			if len(allTargets) == 0 {
+				a.OutputSignal.AddError(errors.New("no targets specified"))
+				return
+			}
+
+			fingerprintsPath, err := cmd.Flags().GetString("fingerprints")
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+
+			onlySuccessful, err := cmd.Flags().GetBool("onlysuccessful")
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+
+			setHTTPS, err := cmd.Flags().GetBool("https")
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+
+			timeout, err := cmd.Flags().GetInt("timeout")
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+
+			resolver, err := resolverFromFlags(cmd)
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+
+			report, err := dns.DetectDomainTakeover(cmd.Context(), allTargets, fingerprintsPath, onlySuccessful, setHTTPS, timeout, resolver)
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+			a.OutputSignal.Content = report
+		},
+	}
+
+	takeoverCmd.Flags().StringSlice("targets", []string{}, "URL targets to analyze")
+	takeoverCmd.Flags().String("fingerprints", "", "Path to fingerprints file (defaults to the bundled fingerprints)")
+	takeoverCmd.Flags().StringSlice("files", []string{}, "Paths to files containing the list of targets")
+	takeoverCmd.Flags().Bool("onlysuccessful", false, "Only check sites with secure SSL")
+	takeoverCmd.Flags().Bool("https", false, "Only check sites with secure SSL")
+	takeoverCmd.Flags().Int("timeout", 10, "Request timeout in seconds")
+	addResolverFlags(takeoverCmd)
+
+	emailCmd := &cobra.Command{
+		Use:   "email",
+		Short: "Check MTA-STS, TLS-RPT, BIMI and DANE for a given domain",
+		Long:  `Check the transport and brand email-security controls of a domain: MTA-STS, TLS-RPT, BIMI and DANE for each MX host`,
+		Run: func(cmd *cobra.Command, args []string) {
+			domain, err := cmd.Flags().GetString("domain")
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+			timeout, err := cmd.Flags().GetInt("timeout")
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+			resolver, err := resolverFromFlags(cmd)
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+			report, err := dns.GetDomainEmailSecurity(cmd.Context(), domain, resolver, timeout)
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+			a.OutputSignal.Content = report
+		},
+	}
+
+	emailCmd.Flags().String("domain", "", "Domain to check email security controls for")
+	emailCmd.Flags().Int("timeout", 10, "HTTP request timeout in seconds for policy, logo and certificate fetches")
+	addResolverFlags(emailCmd)
+	_ = emailCmd.MarkFlagRequired("domain")
+
+	dnssecCmd := &cobra.Command{
+		Use:   "dnssec",
+		Short: "Validate the DNSSEC chain of trust for a given domain",
+		Long:  `Validate the DNSSEC chain of trust for a given domain, from the root trust anchor through the DS and DNSKEY records of every zone cut`,
+		Run: func(cmd *cobra.Command, args []string) {
+			domain, err := cmd.Flags().GetString("domain")
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+			resolver, err := resolverFromFlags(cmd)
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+			report, err := dns.ValidateDomainDNSSEC(cmd.Context(), domain, resolver)
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+			a.OutputSignal.Content = report
+		},
+	}
+
+	dnssecCmd.Flags().String("domain", "", "Domain to validate DNSSEC for")
+	addResolverFlags(dnssecCmd)
+	_ = dnssecCmd.MarkFlagRequired("domain")
+
+	axfrCmd := &cobra.Command{
+		Use:   "axfr",
+		Short: "Check the nameservers of a given domain for zone transfer exposure",
+		Long:  `Attempt AXFR and IXFR zone transfers of a given domain against every IPv4 and IPv6 address of every nameserver`,
+		Run: func(cmd *cobra.Command, args []string) {
+			domain, err := cmd.Flags().GetString("domain")
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+			timeout, err := cmd.Flags().GetInt("timeout")
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+			parallelThreads, err := cmd.Flags().GetInt("threads")
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+			resolver, err := resolverFromFlags(cmd)
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+			report, err := dns.CheckDomainZoneTransfer(cmd.Context(), domain, resolver, timeout, parallelThreads)
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+			a.OutputSignal.Content = report
+		},
+	}
+
+	axfrCmd.Flags().String("domain", "", "Domain to attempt zone transfers of")
+	axfrCmd.Flags().Int("timeout", 10, "Zone transfer connect and read timeout in seconds")
+	axfrCmd.Flags().Int("threads", 4, "Number of zone transfers attempted at once")
+	addResolverFlags(axfrCmd)
+	_ = axfrCmd.MarkFlagRequired("domain")
+
+	delegationCmd := &cobra.Command{
+		Use:   "delegation",
+		Short: "Audit the nameserver delegation of a given domain",
+		Long:  `Compare the parent zone delegation of a given domain with the zone's own NS set and query every nameserver directly for lame delegations, serial drift, missing glue, shared network placement and open recursion`,
+		Run: func(cmd *cobra.Command, args []string) {
+			domain, err := cmd.Flags().GetString("domain")
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+			resolver, err := resolverFromFlags(cmd)
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+			report, err := dns.AuditDomainDelegation(cmd.Context(), domain, resolver)
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+			a.OutputSignal.Content = report
+		},
+	}
+
+	delegationCmd.Flags().String("domain", "", "Domain to audit the delegation of")
+	addResolverFlags(delegationCmd)
+	_ = delegationCmd.MarkFlagRequired("domain")
+
+	traceCmd := &cobra.Command{
+		Use:   "trace",
+		Short: "Trace the iterative resolution of a name from the root servers",
+		Long:  `Resolve a name iteratively from the root servers, like dig +trace, recording every referral, glue record, CNAME hop and query time along the delegation path`,
+		Run: func(cmd *cobra.Command, args []string) {
+			domain, err := cmd.Flags().GetString("domain")
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+			questionType, err := cmd.Flags().GetString("type")
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+			rootServers, err := cmd.Flags().GetStringSlice("root-servers")
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+			resolver, err := resolverFromFlags(cmd)
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+			report, err := dns.TraceDomain(cmd.Context(), domain, questionType, rootServers, resolver)
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+			a.OutputSignal.Content = report
+		},
+	}
+
+	traceCmd.Flags().String("domain", "", "Name to trace the resolution of")
+	traceCmd.Flags().String("type", "A", "Record type to resolve")
+	traceCmd.Flags().StringSlice("root-servers", []string{}, "Servers to start the trace from instead of the IANA root servers")
+	addResolverFlags(traceCmd)
+	_ = traceCmd.MarkFlagRequired("domain")
+
+	ptrCmd := &cobra.Command{
+		Use:   "ptr",
+		Short: "Sweep CIDR ranges and ASNs for PTR records",
+		Long:  `Look up the PTR records of every address in the given CIDR ranges and in the prefixes announced by the given ASNs, optionally keeping only hostnames under the given suffixes`,
+		Run: func(cmd *cobra.Command, args []string) {
+			cidrs, err := cmd.Flags().GetStringSlice("cidrs")
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+			filePaths, err := cmd.Flags().GetStringSlice("files")
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+			fileCidrs, err := utils.GetEntriesFromFiles(filePaths)
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+			asns, err := cmd.Flags().GetStringSlice("asns")
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+			allCidrs := append(cidrs, fileCidrs...)
+			if len(allCidrs) == 0 && len(asns) == 0 {
+				a.OutputSignal.AddError(errors.New("no CIDRs or ASNs specified"))
+				return
+			}
+
+			suffixes, err := cmd.Flags().GetStringSlice("suffix")
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+			parallelThreads, err := cmd.Flags().GetInt("threads")
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+			maxAddresses, err := cmd.Flags().GetInt("max-addresses")
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+			resolver, err := resolverFromFlags(cmd)
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+
+			report, err := dns.SweepPTRRecords(cmd.Context(), allCidrs, asns, suffixes, parallelThreads, maxAddresses, resolver)
+			if err != nil {
+				a.OutputSignal.AddError(err)
+				return
+			}
+			a.OutputSignal.Content = report
+		},
+	}
+
+	ptrCmd.Flags().StringSlice("cidrs", []string{}, "CIDR ranges or IP addresses to sweep")
+	ptrCmd.Flags().StringSlice("files", []string{}, "Paths to files containing CIDR ranges or IP addresses to sweep")
+	ptrCmd.Flags().StringSlice("asns", []string{}, "ASNs whose announced prefixes to sweep")
+	ptrCmd.Flags().StringSlice("suffix", []string{}, "Only keep PTR hostnames under these domain suffixes")
+	ptrCmd.Flags().Int("threads", 50, "Number of parallel PTR lookups")
+	ptrCmd.Flags().Int("max-addresses", 65536, "Maximum number of addresses to sweep; larger prefixes are skipped")
+	addResolverFlags(ptrCmd)
+
+	a.DNSCmd.AddCommand(recordCmd)
+	a.DNSCmd.AddCommand(certsCmd)
+	a.DNSCmd.AddCommand(subenumCmd)
+	a.DNSCmd.AddCommand(takeoverCmd)
+	a.DNSCmd.AddCommand(emailCmd)
+	a.DNSCmd.AddCommand(dnssecCmd)
+	a.DNSCmd.AddCommand(axfrCmd)
+	a.DNSCmd.AddCommand(delegationCmd)
+	a.DNSCmd.AddCommand(traceCmd)
+	a.DNSCmd.AddCommand(ptrCmd)
+	a.RootCmd.AddCommand(a.DNSCmd)
+}
+
+// addResolverFlags registers the flags shared by every command that resolves names through dns.Resolver.
+func addResolverFlags(cmd *cobra.Command) {
+	cmd.Flags().StringSlice("resolvers", []string{}, "DNS resolvers to use (1.1.1.1, tcp:1.1.1.1:53, tls://1.1.1.1, https://cloudflare-dns.com/dns-query)")
+	cmd.Flags().StringSlice("resolvers-file", []string{}, "Paths to files containing DNS resolvers, one per line")
+	cmd.Flags().Int("resolver-timeout", 5, "DNS query timeout in seconds")
+	cmd.Flags().Int("resolver-retries", 3, "Number of resolvers to try before a DNS query fails (at least 1)")
+}
+
+// resolverFromFlags builds a dns.Resolver from the flags registered by addResolverFlags.
+func resolverFromFlags(cmd *cobra.Command) (*dns.Resolver, error) {
+	resolvers, err := cmd.Flags().GetStringSlice("resolvers")
+	if err != nil {
+		return nil, err
+	}
+	resolverFiles, err := cmd.Flags().GetStringSlice("resolvers-file")
+	if err != nil {
+		return nil, err
+	}
+	fileResolvers, err := utils.GetEntriesFromFiles(resolverFiles)
+	if err != nil {
+		return nil, err
+	}
+	timeout, err := cmd.Flags().GetInt("resolver-timeout")
+	if err != nil {
+		return nil, err
+	}
+	retries, err := cmd.Flags().GetInt("resolver-retries")
+	if err != nil {
+		return nil, err
+	}
+	if retries < 1 {
+		return nil, errors.New("--resolver-retries must be at least 1")
+	}
+
+	return dns.NewResolver(dns.ResolverConfig{
+		Servers: append(resolvers, fileResolvers...),
+		Timeout: time.Duration(timeout) * time.Second,
+		Retries: retries,
+	})
 }
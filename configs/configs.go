@@ -0,0 +1,103 @@
+// Package configs embeds the wordlists, DKIM selectors and takeover fingerprints shipped with osintscan, so that the
+// binary works without a checkout of the repository next to it.
+package configs
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"strings"
+)
+
+//go:embed dns/subenum/wordlist-100.txt dns/subenum/wordlist-5000.txt dns/subenum/wordlist-20000.txt dns/subenum/wordlist-110000.txt
+//go:embed dns/subenum/permutation-tokens.txt
+//go:embed dns/takeover/fingerprints.json
+//go:embed dns/dkim/selectors.txt
+var files embed.FS
+
+// wordlists are the bundled subdomain wordlists, from the smallest to the largest.
+var wordlists = []struct {
+	name string
+	path string
+}{
+	{"small", "dns/subenum/wordlist-100.txt"},
+	{"5000", "dns/subenum/wordlist-5000.txt"},
+	{"20000", "dns/subenum/wordlist-20000.txt"},
+	{"110000", "dns/subenum/wordlist-110000.txt"},
+}
+
+const (
+	permutationTokensPath = "dns/subenum/permutation-tokens.txt"
+	fingerprintsPath      = "dns/takeover/fingerprints.json"
+	dkimSelectorsPath     = "dns/dkim/selectors.txt"
+)
+
+// init checks that every file read by this package is embedded, so that a path that does not match its go:embed
+// directive fails as soon as the binary starts rather than when the file is first used.
+func init() {
+	paths := []string{permutationTokensPath, fingerprintsPath, dkimSelectorsPath}
+	for _, wordlist := range wordlists {
+		paths = append(paths, wordlist.path)
+	}
+	for _, path := range paths {
+		if _, err := fs.Stat(files, path); err != nil {
+			panic(fmt.Sprintf("configs: %s is not embedded: %s", path, err))
+		}
+	}
+}
+
+// WordlistNames returns the names of the bundled subdomain wordlists, from the smallest to the largest.
+func WordlistNames() []string {
+	names := make([]string, 0, len(wordlists))
+	for _, wordlist := range wordlists {
+		names = append(names, wordlist.name)
+	}
+	return names
+}
+
+// Wordlist returns the entries of the bundled subdomain wordlist with the given name.
+func Wordlist(name string) ([]string, error) {
+	for _, wordlist := range wordlists {
+		if wordlist.name == name {
+			return lines(wordlist.path), nil
+		}
+	}
+	return nil, fmt.Errorf("unknown wordlist %q, expected one of %s", name, strings.Join(WordlistNames(), ", "))
+}
+
+// PermutationTokens returns the bundled tokens that are inserted into known subdomains to generate variants of them.
+func PermutationTokens() []string {
+	return lines(permutationTokensPath)
+}
+
+// Fingerprints returns the bundled subdomain takeover fingerprints as JSON.
+func Fingerprints() []byte {
+	return readFile(fingerprintsPath)
+}
+
+// DkimSelectors returns the bundled list of common DKIM selectors.
+func DkimSelectors() []string {
+	return lines(dkimSelectorsPath)
+}
+
+// readFile returns the content of an embedded file. The files are part of the binary and init checks that every one of
+// them is there, so failing to read one is a bug rather than an error the caller could handle.
+func readFile(path string) []byte {
+	data, err := files.ReadFile(path)
+	if err != nil {
+		panic(fmt.Sprintf("configs: could not read embedded file %s: %s", path, err))
+	}
+	return data
+}
+
+// lines returns the non-empty lines of an embedded file.
+func lines(path string) []string {
+	data := readFile(path)
+	var entries []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			entries = append(entries, line)
+		}
+	}
+	return entries
+}
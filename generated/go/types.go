@@ -6,29 +6,80 @@ import (
 	json "encoding/json"
 	fmt "fmt"
 	core "github.com/Method-Security/osintscan/generated/go/core"
+	time "time"
 )
 
-type DnsRecord struct {
-	Name  string `json:"name" url:"name"`
-	Ttl   int    `json:"ttl" url:"ttl"`
-	Type  string `json:"type" url:"type"`
-	Value string `json:"value" url:"value"`
+type BimiRecord struct {
+	Status             EmailControlStatus `json:"status" url:"status"`
+	Record             *string            `json:"record,omitempty" url:"record,omitempty"`
+	LogoUrl            *string            `json:"logoUrl,omitempty" url:"logoUrl,omitempty"`
+	LogoReachable      *bool              `json:"logoReachable,omitempty" url:"logoReachable,omitempty"`
+	AuthorityUrl       *string            `json:"authorityUrl,omitempty" url:"authorityUrl,omitempty"`
+	AuthorityReachable *bool              `json:"authorityReachable,omitempty" url:"authorityReachable,omitempty"`
+	Findings           []string           `json:"findings,omitempty" url:"findings,omitempty"`
 
 	extraProperties map[string]interface{}
 	_rawJSON        json.RawMessage
 }
 
-func (d *DnsRecord) GetExtraProperties() map[string]interface{} {
+func (b *BimiRecord) GetExtraProperties() map[string]interface{} {
+	return b.extraProperties
+}
+
+func (b *BimiRecord) UnmarshalJSON(data []byte) error {
+	type unmarshaler BimiRecord
+	var value unmarshaler
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*b = BimiRecord(value)
+
+	extraProperties, err := core.ExtractExtraProperties(data, *b)
+	if err != nil {
+		return err
+	}
+	b.extraProperties = extraProperties
+
+	b._rawJSON = json.RawMessage(data)
+	return nil
+}
+
+func (b *BimiRecord) String() string {
+	if len(b._rawJSON) > 0 {
+		if value, err := core.StringifyJSON(b._rawJSON); err == nil {
+			return value
+		}
+	}
+	if value, err := core.StringifyJSON(b); err == nil {
+		return value
+	}
+	return fmt.Sprintf("%#v", b)
+}
+
+type DaneMxHost struct {
+	Status          EmailControlStatus `json:"status" url:"status"`
+	Mx              string             `json:"mx" url:"mx"`
+	Preference      int                `json:"preference" url:"preference"`
+	TlsaName        string             `json:"tlsaName" url:"tlsaName"`
+	TlsaRecords     []*DnsRecord       `json:"tlsaRecords,omitempty" url:"tlsaRecords,omitempty"`
+	DnssecValidated bool               `json:"dnssecValidated" url:"dnssecValidated"`
+	Findings        []string           `json:"findings,omitempty" url:"findings,omitempty"`
+
+	extraProperties map[string]interface{}
+	_rawJSON        json.RawMessage
+}
+
+func (d *DaneMxHost) GetExtraProperties() map[string]interface{} {
 	return d.extraProperties
 }
 
-func (d *DnsRecord) UnmarshalJSON(data []byte) error {
-	type unmarshaler DnsRecord
+func (d *DaneMxHost) UnmarshalJSON(data []byte) error {
+	type unmarshaler DaneMxHost
 	var value unmarshaler
 	if err := json.Unmarshal(data, &value); err != nil {
 		return err
 	}
-	*d = DnsRecord(value)
+	*d = DaneMxHost(value)
 
 	extraProperties, err := core.ExtractExtraProperties(data, *d)
 	if err != nil {
@@ -40,7 +91,7 @@ func (d *DnsRecord) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
-func (d *DnsRecord) String() string {
+func (d *DaneMxHost) String() string {
 	if len(d._rawJSON) > 0 {
 		if value, err := core.StringifyJSON(d._rawJSON); err == nil {
 			return value
@@ -52,29 +103,26 @@ func (d *DnsRecord) String() string {
 	return fmt.Sprintf("%#v", d)
 }
 
-type DnsRecords struct {
-	A     []*DnsRecord `json:"a,omitempty" url:"a,omitempty"`
-	Aaaa  []*DnsRecord `json:"aaaa,omitempty" url:"aaaa,omitempty"`
-	Mx    []*DnsRecord `json:"mx,omitempty" url:"mx,omitempty"`
-	Txt   []*DnsRecord `json:"txt,omitempty" url:"txt,omitempty"`
-	Ns    []*DnsRecord `json:"ns,omitempty" url:"ns,omitempty"`
-	Cname []*DnsRecord `json:"cname,omitempty" url:"cname,omitempty"`
+type DanePolicy struct {
+	Status   EmailControlStatus `json:"status" url:"status"`
+	MxHosts  []*DaneMxHost      `json:"mxHosts,omitempty" url:"mxHosts,omitempty"`
+	Findings []string           `json:"findings,omitempty" url:"findings,omitempty"`
 
 	extraProperties map[string]interface{}
 	_rawJSON        json.RawMessage
 }
 
-func (d *DnsRecords) GetExtraProperties() map[string]interface{} {
+func (d *DanePolicy) GetExtraProperties() map[string]interface{} {
 	return d.extraProperties
 }
 
-func (d *DnsRecords) UnmarshalJSON(data []byte) error {
-	type unmarshaler DnsRecords
+func (d *DanePolicy) UnmarshalJSON(data []byte) error {
+	type unmarshaler DanePolicy
 	var value unmarshaler
 	if err := json.Unmarshal(data, &value); err != nil {
 		return err
 	}
-	*d = DnsRecords(value)
+	*d = DanePolicy(value)
 
 	extraProperties, err := core.ExtractExtraProperties(data, *d)
 	if err != nil {
@@ -86,7 +134,7 @@ func (d *DnsRecords) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
-func (d *DnsRecords) String() string {
+func (d *DanePolicy) String() string {
 	if len(d._rawJSON) > 0 {
 		if value, err := core.StringifyJSON(d._rawJSON); err == nil {
 			return value
@@ -98,30 +146,28 @@ func (d *DnsRecords) String() string {
 	return fmt.Sprintf("%#v", d)
 }
 
-type DnsRecordsReport struct {
-	Domain          string      `json:"domain" url:"domain"`
-	DnsRecords      *DnsRecords `json:"dnsRecords,omitempty" url:"dnsRecords,omitempty"`
-	DmarcDomain     *string     `json:"dmarcDomain,omitempty" url:"dmarcDomain,omitempty"`
-	DmarcDnsRecords *DnsRecords `json:"dmarcDnsRecords,omitempty" url:"dmarcDnsRecords,omitempty"`
-	DkimDomain      *string     `json:"dkimDomain,omitempty" url:"dkimDomain,omitempty"`
-	DkimDnsRecords  *DnsRecords `json:"dkimDnsRecords,omitempty" url:"dkimDnsRecords,omitempty"`
-	Errors          []string    `json:"errors,omitempty" url:"errors,omitempty"`
+type DelegationNameserver struct {
+	Name        string                         `json:"name" url:"name"`
+	InParent    bool                           `json:"inParent" url:"inParent"`
+	InChild     bool                           `json:"inChild" url:"inChild"`
+	InBailiwick bool                           `json:"inBailiwick" url:"inBailiwick"`
+	Addresses   []*DelegationNameserverAddress `json:"addresses,omitempty" url:"addresses,omitempty"`
 
 	extraProperties map[string]interface{}
 	_rawJSON        json.RawMessage
 }
 
-func (d *DnsRecordsReport) GetExtraProperties() map[string]interface{} {
+func (d *DelegationNameserver) GetExtraProperties() map[string]interface{} {
 	return d.extraProperties
 }
 
-func (d *DnsRecordsReport) UnmarshalJSON(data []byte) error {
-	type unmarshaler DnsRecordsReport
+func (d *DelegationNameserver) UnmarshalJSON(data []byte) error {
+	type unmarshaler DelegationNameserver
 	var value unmarshaler
 	if err := json.Unmarshal(data, &value); err != nil {
 		return err
 	}
-	*d = DnsRecordsReport(value)
+	*d = DelegationNameserver(value)
 
 	extraProperties, err := core.ExtractExtraProperties(data, *d)
 	if err != nil {
@@ -133,7 +179,7 @@ func (d *DnsRecordsReport) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
-func (d *DnsRecordsReport) String() string {
+func (d *DelegationNameserver) String() string {
 	if len(d._rawJSON) > 0 {
 		if value, err := core.StringifyJSON(d._rawJSON); err == nil {
 			return value
@@ -145,27 +191,35 @@ func (d *DnsRecordsReport) String() string {
 	return fmt.Sprintf("%#v", d)
 }
 
-type DnsSubenumReport struct {
-	Domain          string         `json:"domain" url:"domain"`
-	EnumerationType DnsSubenumType `json:"enumerationType" url:"enumerationType"`
-	Subdomains      []string       `json:"subdomains,omitempty" url:"subdomains,omitempty"`
-	Errors          []string       `json:"errors,omitempty" url:"errors,omitempty"`
+type DelegationNameserverAddress struct {
+	Address            string   `json:"address" url:"address"`
+	Glue               bool     `json:"glue" url:"glue"`
+	Asn                *int     `json:"asn,omitempty" url:"asn,omitempty"`
+	AsnPrefix          *string  `json:"asnPrefix,omitempty" url:"asnPrefix,omitempty"`
+	Authoritative      bool     `json:"authoritative" url:"authoritative"`
+	Lame               bool     `json:"lame" url:"lame"`
+	RecursionAvailable bool     `json:"recursionAvailable" url:"recursionAvailable"`
+	Rcode              *string  `json:"rcode,omitempty" url:"rcode,omitempty"`
+	Serial             *int64   `json:"serial,omitempty" url:"serial,omitempty"`
+	Nameservers        []string `json:"nameservers,omitempty" url:"nameservers,omitempty"`
+	QueryTimeMs        *int     `json:"queryTimeMs,omitempty" url:"queryTimeMs,omitempty"`
+	Error              *string  `json:"error,omitempty" url:"error,omitempty"`
 
 	extraProperties map[string]interface{}
 	_rawJSON        json.RawMessage
 }
 
-func (d *DnsSubenumReport) GetExtraProperties() map[string]interface{} {
+func (d *DelegationNameserverAddress) GetExtraProperties() map[string]interface{} {
 	return d.extraProperties
 }
 
-func (d *DnsSubenumReport) UnmarshalJSON(data []byte) error {
-	type unmarshaler DnsSubenumReport
+func (d *DelegationNameserverAddress) UnmarshalJSON(data []byte) error {
+	type unmarshaler DelegationNameserverAddress
 	var value unmarshaler
 	if err := json.Unmarshal(data, &value); err != nil {
 		return err
 	}
-	*d = DnsSubenumReport(value)
+	*d = DelegationNameserverAddress(value)
 
 	extraProperties, err := core.ExtractExtraProperties(data, *d)
 	if err != nil {
@@ -177,7 +231,7 @@ func (d *DnsSubenumReport) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
-func (d *DnsSubenumReport) String() string {
+func (d *DelegationNameserverAddress) String() string {
 	if len(d._rawJSON) > 0 {
 		if value, err := core.StringifyJSON(d._rawJSON); err == nil {
 			return value
@@ -189,51 +243,84 @@ func (d *DnsSubenumReport) String() string {
 	return fmt.Sprintf("%#v", d)
 }
 
-type DnsSubenumType string
+type DelegationReport struct {
+	Domain            string                  `json:"domain" url:"domain"`
+	ParentZone        string                  `json:"parentZone" url:"parentZone"`
+	ParentNameservers []string                `json:"parentNameservers,omitempty" url:"parentNameservers,omitempty"`
+	ChildNameservers  []string                `json:"childNameservers,omitempty" url:"childNameservers,omitempty"`
+	Nameservers       []*DelegationNameserver `json:"nameservers,omitempty" url:"nameservers,omitempty"`
+	Findings          []string                `json:"findings,omitempty" url:"findings,omitempty"`
+	Errors            []string                `json:"errors,omitempty" url:"errors,omitempty"`
 
-const (
-	DnsSubenumTypeBrute   DnsSubenumType = "BRUTE"
-	DnsSubenumTypePassive DnsSubenumType = "PASSIVE"
-)
+	extraProperties map[string]interface{}
+	_rawJSON        json.RawMessage
+}
 
-func NewDnsSubenumTypeFromString(s string) (DnsSubenumType, error) {
-	switch s {
-	case "BRUTE":
-		return DnsSubenumTypeBrute, nil
-	case "PASSIVE":
-		return DnsSubenumTypePassive, nil
+func (d *DelegationReport) GetExtraProperties() map[string]interface{} {
+	return d.extraProperties
+}
+
+func (d *DelegationReport) UnmarshalJSON(data []byte) error {
+	type unmarshaler DelegationReport
+	var value unmarshaler
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
 	}
-	var t DnsSubenumType
-	return "", fmt.Errorf("%s is not a valid %T", s, t)
+	*d = DelegationReport(value)
+
+	extraProperties, err := core.ExtractExtraProperties(data, *d)
+	if err != nil {
+		return err
+	}
+	d.extraProperties = extraProperties
+
+	d._rawJSON = json.RawMessage(data)
+	return nil
 }
 
-func (d DnsSubenumType) Ptr() *DnsSubenumType {
-	return &d
+func (d *DelegationReport) String() string {
+	if len(d._rawJSON) > 0 {
+		if value, err := core.StringifyJSON(d._rawJSON); err == nil {
+			return value
+		}
+	}
+	if value, err := core.StringifyJSON(d); err == nil {
+		return value
+	}
+	return fmt.Sprintf("%#v", d)
 }
 
-type DomainTakeover struct {
-	Target       string     `json:"target" url:"target"`
-	StatusCode   int        `json:"statusCode" url:"statusCode"`
-	ResponseBody string     `json:"responseBody" url:"responseBody"`
-	Domain       string     `json:"domain" url:"domain"`
-	Cname        string     `json:"cname" url:"cname"`
-	Services     []*Service `json:"services,omitempty" url:"services,omitempty"`
+type DkimKey struct {
+	Selector  string   `json:"selector" url:"selector"`
+	Domain    string   `json:"domain" url:"domain"`
+	Record    string   `json:"record" url:"record"`
+	V         *string  `json:"v,omitempty" url:"v,omitempty"`
+	K         string   `json:"k" url:"k"`
+	P         *string  `json:"p,omitempty" url:"p,omitempty"`
+	T         []string `json:"t,omitempty" url:"t,omitempty"`
+	S         []string `json:"s,omitempty" url:"s,omitempty"`
+	H         []string `json:"h,omitempty" url:"h,omitempty"`
+	KeyLength *int     `json:"keyLength,omitempty" url:"keyLength,omitempty"`
+	Revoked   bool     `json:"revoked" url:"revoked"`
+	TestMode  bool     `json:"testMode" url:"testMode"`
+	WeakKey   bool     `json:"weakKey" url:"weakKey"`
+	Findings  []string `json:"findings,omitempty" url:"findings,omitempty"`
 
 	extraProperties map[string]interface{}
 	_rawJSON        json.RawMessage
 }
 
-func (d *DomainTakeover) GetExtraProperties() map[string]interface{} {
+func (d *DkimKey) GetExtraProperties() map[string]interface{} {
 	return d.extraProperties
 }
 
-func (d *DomainTakeover) UnmarshalJSON(data []byte) error {
-	type unmarshaler DomainTakeover
+func (d *DkimKey) UnmarshalJSON(data []byte) error {
+	type unmarshaler DkimKey
 	var value unmarshaler
 	if err := json.Unmarshal(data, &value); err != nil {
 		return err
 	}
-	*d = DomainTakeover(value)
+	*d = DkimKey(value)
 
 	extraProperties, err := core.ExtractExtraProperties(data, *d)
 	if err != nil {
@@ -245,7 +332,7 @@ func (d *DomainTakeover) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
-func (d *DomainTakeover) String() string {
+func (d *DkimKey) String() string {
 	if len(d._rawJSON) > 0 {
 		if value, err := core.StringifyJSON(d._rawJSON); err == nil {
 			return value
@@ -257,25 +344,38 @@ func (d *DomainTakeover) String() string {
 	return fmt.Sprintf("%#v", d)
 }
 
-type DomainTakeoverReport struct {
-	DomainTakeovers []*DomainTakeover `json:"domainTakeovers,omitempty" url:"domainTakeovers,omitempty"`
-	Errors          []string          `json:"errors,omitempty" url:"errors,omitempty"`
+type DmarcPolicy struct {
+	Domain               string                    `json:"domain" url:"domain"`
+	Record               string                    `json:"record" url:"record"`
+	OrganizationalDomain *string                   `json:"organizationalDomain,omitempty" url:"organizationalDomain,omitempty"`
+	Inherited            bool                      `json:"inherited" url:"inherited"`
+	DuplicateRecords     bool                      `json:"duplicateRecords" url:"duplicateRecords"`
+	P                    *string                   `json:"p,omitempty" url:"p,omitempty"`
+	Sp                   *string                   `json:"sp,omitempty" url:"sp,omitempty"`
+	Pct                  int                       `json:"pct" url:"pct"`
+	Rua                  []*DmarcReportDestination `json:"rua,omitempty" url:"rua,omitempty"`
+	Ruf                  []*DmarcReportDestination `json:"ruf,omitempty" url:"ruf,omitempty"`
+	Adkim                string                    `json:"adkim" url:"adkim"`
+	Aspf                 string                    `json:"aspf" url:"aspf"`
+	Fo                   string                    `json:"fo" url:"fo"`
+	EffectivePolicy      *string                   `json:"effectivePolicy,omitempty" url:"effectivePolicy,omitempty"`
+	Findings             []string                  `json:"findings,omitempty" url:"findings,omitempty"`
 
 	extraProperties map[string]interface{}
 	_rawJSON        json.RawMessage
 }
 
-func (d *DomainTakeoverReport) GetExtraProperties() map[string]interface{} {
+func (d *DmarcPolicy) GetExtraProperties() map[string]interface{} {
 	return d.extraProperties
 }
 
-func (d *DomainTakeoverReport) UnmarshalJSON(data []byte) error {
-	type unmarshaler DomainTakeoverReport
+func (d *DmarcPolicy) UnmarshalJSON(data []byte) error {
+	type unmarshaler DmarcPolicy
 	var value unmarshaler
 	if err := json.Unmarshal(data, &value); err != nil {
 		return err
 	}
-	*d = DomainTakeoverReport(value)
+	*d = DmarcPolicy(value)
 
 	extraProperties, err := core.ExtractExtraProperties(data, *d)
 	if err != nil {
@@ -287,7 +387,7 @@ func (d *DomainTakeoverReport) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
-func (d *DomainTakeoverReport) String() string {
+func (d *DmarcPolicy) String() string {
 	if len(d._rawJSON) > 0 {
 		if value, err := core.StringifyJSON(d._rawJSON); err == nil {
 			return value
@@ -299,95 +399,2434 @@ func (d *DomainTakeoverReport) String() string {
 	return fmt.Sprintf("%#v", d)
 }
 
-type Fingerprint struct {
-	CicdPass      bool     `json:"cicdPass" url:"cicdPass"`
-	Cname         []string `json:"cname,omitempty" url:"cname,omitempty"`
-	Discussion    string   `json:"discussion" url:"discussion"`
-	Documentation string   `json:"documentation" url:"documentation"`
-	Fingerprint   string   `json:"fingerprint" url:"fingerprint"`
-	HttpStatus    *int     `json:"httpStatus,omitempty" url:"httpStatus,omitempty"`
-	NxDomain      bool     `json:"nxDomain" url:"nxDomain"`
-	Service       string   `json:"service" url:"service"`
-	Status        string   `json:"status" url:"status"`
-	Vulnerable    bool     `json:"vulnerable" url:"vulnerable"`
+type DmarcReportDestination struct {
+	Uri                 string  `json:"uri" url:"uri"`
+	Domain              string  `json:"domain" url:"domain"`
+	External            bool    `json:"external" url:"external"`
+	Authorized          *bool   `json:"authorized,omitempty" url:"authorized,omitempty"`
+	AuthorizationRecord *string `json:"authorizationRecord,omitempty" url:"authorizationRecord,omitempty"`
 
 	extraProperties map[string]interface{}
 	_rawJSON        json.RawMessage
 }
 
-func (f *Fingerprint) GetExtraProperties() map[string]interface{} {
-	return f.extraProperties
+func (d *DmarcReportDestination) GetExtraProperties() map[string]interface{} {
+	return d.extraProperties
 }
 
-func (f *Fingerprint) UnmarshalJSON(data []byte) error {
-	type unmarshaler Fingerprint
+func (d *DmarcReportDestination) UnmarshalJSON(data []byte) error {
+	type unmarshaler DmarcReportDestination
 	var value unmarshaler
 	if err := json.Unmarshal(data, &value); err != nil {
 		return err
 	}
-	*f = Fingerprint(value)
+	*d = DmarcReportDestination(value)
 
-	extraProperties, err := core.ExtractExtraProperties(data, *f)
+	extraProperties, err := core.ExtractExtraProperties(data, *d)
 	if err != nil {
 		return err
 	}
-	f.extraProperties = extraProperties
+	d.extraProperties = extraProperties
 
-	f._rawJSON = json.RawMessage(data)
+	d._rawJSON = json.RawMessage(data)
 	return nil
 }
 
-func (f *Fingerprint) String() string {
-	if len(f._rawJSON) > 0 {
-		if value, err := core.StringifyJSON(f._rawJSON); err == nil {
+func (d *DmarcReportDestination) String() string {
+	if len(d._rawJSON) > 0 {
+		if value, err := core.StringifyJSON(d._rawJSON); err == nil {
 			return value
 		}
 	}
-	if value, err := core.StringifyJSON(f); err == nil {
+	if value, err := core.StringifyJSON(d); err == nil {
 		return value
 	}
-	return fmt.Sprintf("%#v", f)
+	return fmt.Sprintf("%#v", d)
 }
 
-type Service struct {
-	Name        string `json:"name" url:"name"`
-	Fingerprint string `json:"fingerprint" url:"fingerprint"`
-	Vulnerable  bool   `json:"vulnerable" url:"vulnerable"`
+type DnsCaaData struct {
+	Flag  int    `json:"flag" url:"flag"`
+	Tag   string `json:"tag" url:"tag"`
+	Value string `json:"value" url:"value"`
 
 	extraProperties map[string]interface{}
 	_rawJSON        json.RawMessage
 }
 
-func (s *Service) GetExtraProperties() map[string]interface{} {
-	return s.extraProperties
+func (d *DnsCaaData) GetExtraProperties() map[string]interface{} {
+	return d.extraProperties
 }
 
-func (s *Service) UnmarshalJSON(data []byte) error {
-	type unmarshaler Service
+func (d *DnsCaaData) UnmarshalJSON(data []byte) error {
+	type unmarshaler DnsCaaData
 	var value unmarshaler
 	if err := json.Unmarshal(data, &value); err != nil {
 		return err
 	}
-	*s = Service(value)
+	*d = DnsCaaData(value)
 
-	extraProperties, err := core.ExtractExtraProperties(data, *s)
+	extraProperties, err := core.ExtractExtraProperties(data, *d)
 	if err != nil {
 		return err
 	}
-	s.extraProperties = extraProperties
+	d.extraProperties = extraProperties
 
-	s._rawJSON = json.RawMessage(data)
+	d._rawJSON = json.RawMessage(data)
 	return nil
 }
 
-func (s *Service) String() string {
-	if len(s._rawJSON) > 0 {
-		if value, err := core.StringifyJSON(s._rawJSON); err == nil {
+func (d *DnsCaaData) String() string {
+	if len(d._rawJSON) > 0 {
+		if value, err := core.StringifyJSON(d._rawJSON); err == nil {
 			return value
 		}
 	}
-	if value, err := core.StringifyJSON(s); err == nil {
+	if value, err := core.StringifyJSON(d); err == nil {
 		return value
 	}
-	return fmt.Sprintf("%#v", s)
+	return fmt.Sprintf("%#v", d)
+}
+
+type DnsDanglingCname struct {
+	Name       string   `json:"name" url:"name"`
+	Chain      []string `json:"chain,omitempty" url:"chain,omitempty"`
+	Rcode      string   `json:"rcode" url:"rcode"`
+	Service    *string  `json:"service,omitempty" url:"service,omitempty"`
+	Vulnerable *bool    `json:"vulnerable,omitempty" url:"vulnerable,omitempty"`
+
+	extraProperties map[string]interface{}
+	_rawJSON        json.RawMessage
+}
+
+func (d *DnsDanglingCname) GetExtraProperties() map[string]interface{} {
+	return d.extraProperties
+}
+
+func (d *DnsDanglingCname) UnmarshalJSON(data []byte) error {
+	type unmarshaler DnsDanglingCname
+	var value unmarshaler
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*d = DnsDanglingCname(value)
+
+	extraProperties, err := core.ExtractExtraProperties(data, *d)
+	if err != nil {
+		return err
+	}
+	d.extraProperties = extraProperties
+
+	d._rawJSON = json.RawMessage(data)
+	return nil
+}
+
+func (d *DnsDanglingCname) String() string {
+	if len(d._rawJSON) > 0 {
+		if value, err := core.StringifyJSON(d._rawJSON); err == nil {
+			return value
+		}
+	}
+	if value, err := core.StringifyJSON(d); err == nil {
+		return value
+	}
+	return fmt.Sprintf("%#v", d)
+}
+
+type DnsDnskeyData struct {
+	Flags         int    `json:"flags" url:"flags"`
+	Protocol      int    `json:"protocol" url:"protocol"`
+	Algorithm     int    `json:"algorithm" url:"algorithm"`
+	AlgorithmName string `json:"algorithmName" url:"algorithmName"`
+	KeyTag        int    `json:"keyTag" url:"keyTag"`
+	PublicKey     string `json:"publicKey" url:"publicKey"`
+
+	extraProperties map[string]interface{}
+	_rawJSON        json.RawMessage
+}
+
+func (d *DnsDnskeyData) GetExtraProperties() map[string]interface{} {
+	return d.extraProperties
+}
+
+func (d *DnsDnskeyData) UnmarshalJSON(data []byte) error {
+	type unmarshaler DnsDnskeyData
+	var value unmarshaler
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*d = DnsDnskeyData(value)
+
+	extraProperties, err := core.ExtractExtraProperties(data, *d)
+	if err != nil {
+		return err
+	}
+	d.extraProperties = extraProperties
+
+	d._rawJSON = json.RawMessage(data)
+	return nil
+}
+
+func (d *DnsDnskeyData) String() string {
+	if len(d._rawJSON) > 0 {
+		if value, err := core.StringifyJSON(d._rawJSON); err == nil {
+			return value
+		}
+	}
+	if value, err := core.StringifyJSON(d); err == nil {
+		return value
+	}
+	return fmt.Sprintf("%#v", d)
+}
+
+type DnsDsData struct {
+	KeyTag        int    `json:"keyTag" url:"keyTag"`
+	Algorithm     int    `json:"algorithm" url:"algorithm"`
+	AlgorithmName string `json:"algorithmName" url:"algorithmName"`
+	DigestType    int    `json:"digestType" url:"digestType"`
+	Digest        string `json:"digest" url:"digest"`
+
+	extraProperties map[string]interface{}
+	_rawJSON        json.RawMessage
+}
+
+func (d *DnsDsData) GetExtraProperties() map[string]interface{} {
+	return d.extraProperties
+}
+
+func (d *DnsDsData) UnmarshalJSON(data []byte) error {
+	type unmarshaler DnsDsData
+	var value unmarshaler
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*d = DnsDsData(value)
+
+	extraProperties, err := core.ExtractExtraProperties(data, *d)
+	if err != nil {
+		return err
+	}
+	d.extraProperties = extraProperties
+
+	d._rawJSON = json.RawMessage(data)
+	return nil
+}
+
+func (d *DnsDsData) String() string {
+	if len(d._rawJSON) > 0 {
+		if value, err := core.StringifyJSON(d._rawJSON); err == nil {
+			return value
+		}
+	}
+	if value, err := core.StringifyJSON(d); err == nil {
+		return value
+	}
+	return fmt.Sprintf("%#v", d)
+}
+
+type DnsMxData struct {
+	Preference int    `json:"preference" url:"preference"`
+	Exchange   string `json:"exchange" url:"exchange"`
+
+	extraProperties map[string]interface{}
+	_rawJSON        json.RawMessage
+}
+
+func (d *DnsMxData) GetExtraProperties() map[string]interface{} {
+	return d.extraProperties
+}
+
+func (d *DnsMxData) UnmarshalJSON(data []byte) error {
+	type unmarshaler DnsMxData
+	var value unmarshaler
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*d = DnsMxData(value)
+
+	extraProperties, err := core.ExtractExtraProperties(data, *d)
+	if err != nil {
+		return err
+	}
+	d.extraProperties = extraProperties
+
+	d._rawJSON = json.RawMessage(data)
+	return nil
+}
+
+func (d *DnsMxData) String() string {
+	if len(d._rawJSON) > 0 {
+		if value, err := core.StringifyJSON(d._rawJSON); err == nil {
+			return value
+		}
+	}
+	if value, err := core.StringifyJSON(d); err == nil {
+		return value
+	}
+	return fmt.Sprintf("%#v", d)
+}
+
+type DnsNaptrData struct {
+	Order       int    `json:"order" url:"order"`
+	Preference  int    `json:"preference" url:"preference"`
+	Flags       string `json:"flags" url:"flags"`
+	Service     string `json:"service" url:"service"`
+	Regexp      string `json:"regexp" url:"regexp"`
+	Replacement string `json:"replacement" url:"replacement"`
+
+	extraProperties map[string]interface{}
+	_rawJSON        json.RawMessage
+}
+
+func (d *DnsNaptrData) GetExtraProperties() map[string]interface{} {
+	return d.extraProperties
+}
+
+func (d *DnsNaptrData) UnmarshalJSON(data []byte) error {
+	type unmarshaler DnsNaptrData
+	var value unmarshaler
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*d = DnsNaptrData(value)
+
+	extraProperties, err := core.ExtractExtraProperties(data, *d)
+	if err != nil {
+		return err
+	}
+	d.extraProperties = extraProperties
+
+	d._rawJSON = json.RawMessage(data)
+	return nil
+}
+
+func (d *DnsNaptrData) String() string {
+	if len(d._rawJSON) > 0 {
+		if value, err := core.StringifyJSON(d._rawJSON); err == nil {
+			return value
+		}
+	}
+	if value, err := core.StringifyJSON(d); err == nil {
+		return value
+	}
+	return fmt.Sprintf("%#v", d)
+}
+
+type DnsRecord struct {
+	Name     string               `json:"name" url:"name"`
+	Ttl      int                  `json:"ttl" url:"ttl"`
+	Type     string               `json:"type" url:"type"`
+	Value    string               `json:"value" url:"value"`
+	Mx       *DnsMxData           `json:"mx,omitempty" url:"mx,omitempty"`
+	Soa      *DnsSoaData          `json:"soa,omitempty" url:"soa,omitempty"`
+	Caa      *DnsCaaData          `json:"caa,omitempty" url:"caa,omitempty"`
+	Srv      *DnsSrvData          `json:"srv,omitempty" url:"srv,omitempty"`
+	Dnskey   *DnsDnskeyData       `json:"dnskey,omitempty" url:"dnskey,omitempty"`
+	Ds       *DnsDsData           `json:"ds,omitempty" url:"ds,omitempty"`
+	Tlsa     *DnsTlsaData         `json:"tlsa,omitempty" url:"tlsa,omitempty"`
+	Svcb     *DnsSvcbData         `json:"svcb,omitempty" url:"svcb,omitempty"`
+	Naptr    *DnsNaptrData        `json:"naptr,omitempty" url:"naptr,omitempty"`
+	Response *DnsResponseMetadata `json:"response,omitempty" url:"response,omitempty"`
+
+	extraProperties map[string]interface{}
+	_rawJSON        json.RawMessage
+}
+
+func (d *DnsRecord) GetExtraProperties() map[string]interface{} {
+	return d.extraProperties
+}
+
+func (d *DnsRecord) UnmarshalJSON(data []byte) error {
+	type unmarshaler DnsRecord
+	var value unmarshaler
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*d = DnsRecord(value)
+
+	extraProperties, err := core.ExtractExtraProperties(data, *d)
+	if err != nil {
+		return err
+	}
+	d.extraProperties = extraProperties
+
+	d._rawJSON = json.RawMessage(data)
+	return nil
+}
+
+func (d *DnsRecord) String() string {
+	if len(d._rawJSON) > 0 {
+		if value, err := core.StringifyJSON(d._rawJSON); err == nil {
+			return value
+		}
+	}
+	if value, err := core.StringifyJSON(d); err == nil {
+		return value
+	}
+	return fmt.Sprintf("%#v", d)
+}
+
+type DnsRecords struct {
+	A      []*DnsRecord `json:"a,omitempty" url:"a,omitempty"`
+	Aaaa   []*DnsRecord `json:"aaaa,omitempty" url:"aaaa,omitempty"`
+	Mx     []*DnsRecord `json:"mx,omitempty" url:"mx,omitempty"`
+	Txt    []*DnsRecord `json:"txt,omitempty" url:"txt,omitempty"`
+	Ns     []*DnsRecord `json:"ns,omitempty" url:"ns,omitempty"`
+	Cname  []*DnsRecord `json:"cname,omitempty" url:"cname,omitempty"`
+	Soa    []*DnsRecord `json:"soa,omitempty" url:"soa,omitempty"`
+	Caa    []*DnsRecord `json:"caa,omitempty" url:"caa,omitempty"`
+	Srv    []*DnsRecord `json:"srv,omitempty" url:"srv,omitempty"`
+	Ptr    []*DnsRecord `json:"ptr,omitempty" url:"ptr,omitempty"`
+	Dnskey []*DnsRecord `json:"dnskey,omitempty" url:"dnskey,omitempty"`
+	Ds     []*DnsRecord `json:"ds,omitempty" url:"ds,omitempty"`
+	Tlsa   []*DnsRecord `json:"tlsa,omitempty" url:"tlsa,omitempty"`
+	Https  []*DnsRecord `json:"https,omitempty" url:"https,omitempty"`
+	Svcb   []*DnsRecord `json:"svcb,omitempty" url:"svcb,omitempty"`
+	Naptr  []*DnsRecord `json:"naptr,omitempty" url:"naptr,omitempty"`
+
+	extraProperties map[string]interface{}
+	_rawJSON        json.RawMessage
+}
+
+func (d *DnsRecords) GetExtraProperties() map[string]interface{} {
+	return d.extraProperties
+}
+
+func (d *DnsRecords) UnmarshalJSON(data []byte) error {
+	type unmarshaler DnsRecords
+	var value unmarshaler
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*d = DnsRecords(value)
+
+	extraProperties, err := core.ExtractExtraProperties(data, *d)
+	if err != nil {
+		return err
+	}
+	d.extraProperties = extraProperties
+
+	d._rawJSON = json.RawMessage(data)
+	return nil
+}
+
+func (d *DnsRecords) String() string {
+	if len(d._rawJSON) > 0 {
+		if value, err := core.StringifyJSON(d._rawJSON); err == nil {
+			return value
+		}
+	}
+	if value, err := core.StringifyJSON(d); err == nil {
+		return value
+	}
+	return fmt.Sprintf("%#v", d)
+}
+
+type DnsRecordsReport struct {
+	Domain          string                  `json:"domain" url:"domain"`
+	DnsRecords      *DnsRecords             `json:"dnsRecords,omitempty" url:"dnsRecords,omitempty"`
+	DmarcDomain     *string                 `json:"dmarcDomain,omitempty" url:"dmarcDomain,omitempty"`
+	DmarcDnsRecords *DnsRecords             `json:"dmarcDnsRecords,omitempty" url:"dmarcDnsRecords,omitempty"`
+	DkimDomain      *string                 `json:"dkimDomain,omitempty" url:"dkimDomain,omitempty"`
+	DkimDnsRecords  *DnsRecords             `json:"dkimDnsRecords,omitempty" url:"dkimDnsRecords,omitempty"`
+	DkimKeys        []*DkimKey              `json:"dkimKeys,omitempty" url:"dkimKeys,omitempty"`
+	SpfPolicy       *SpfPolicy              `json:"spfPolicy,omitempty" url:"spfPolicy,omitempty"`
+	DmarcPolicy     *DmarcPolicy            `json:"dmarcPolicy,omitempty" url:"dmarcPolicy,omitempty"`
+	Spoofability    *SpoofabilityAssessment `json:"spoofability,omitempty" url:"spoofability,omitempty"`
+	Errors          []string                `json:"errors,omitempty" url:"errors,omitempty"`
+
+	extraProperties map[string]interface{}
+	_rawJSON        json.RawMessage
+}
+
+func (d *DnsRecordsReport) GetExtraProperties() map[string]interface{} {
+	return d.extraProperties
+}
+
+func (d *DnsRecordsReport) UnmarshalJSON(data []byte) error {
+	type unmarshaler DnsRecordsReport
+	var value unmarshaler
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*d = DnsRecordsReport(value)
+
+	extraProperties, err := core.ExtractExtraProperties(data, *d)
+	if err != nil {
+		return err
+	}
+	d.extraProperties = extraProperties
+
+	d._rawJSON = json.RawMessage(data)
+	return nil
+}
+
+func (d *DnsRecordsReport) String() string {
+	if len(d._rawJSON) > 0 {
+		if value, err := core.StringifyJSON(d._rawJSON); err == nil {
+			return value
+		}
+	}
+	if value, err := core.StringifyJSON(d); err == nil {
+		return value
+	}
+	return fmt.Sprintf("%#v", d)
+}
+
+type DnsResolutionStatus string
+
+const (
+	DnsResolutionStatusLive     DnsResolutionStatus = "LIVE"
+	DnsResolutionStatusDead     DnsResolutionStatus = "DEAD"
+	DnsResolutionStatusWildcard DnsResolutionStatus = "WILDCARD"
+)
+
+func NewDnsResolutionStatusFromString(s string) (DnsResolutionStatus, error) {
+	switch s {
+	case "LIVE":
+		return DnsResolutionStatusLive, nil
+	case "DEAD":
+		return DnsResolutionStatusDead, nil
+	case "WILDCARD":
+		return DnsResolutionStatusWildcard, nil
+	}
+	var t DnsResolutionStatus
+	return "", fmt.Errorf("%s is not a valid %T", s, t)
+}
+
+func (d DnsResolutionStatus) Ptr() *DnsResolutionStatus {
+	return &d
+}
+
+type DnsResponseMetadata struct {
+	Resolver          string `json:"resolver" url:"resolver"`
+	Authoritative     bool   `json:"authoritative" url:"authoritative"`
+	AuthenticatedData bool   `json:"authenticatedData" url:"authenticatedData"`
+	Rcode             string `json:"rcode" url:"rcode"`
+	QueryTimeMs       int    `json:"queryTimeMs" url:"queryTimeMs"`
+
+	extraProperties map[string]interface{}
+	_rawJSON        json.RawMessage
+}
+
+func (d *DnsResponseMetadata) GetExtraProperties() map[string]interface{} {
+	return d.extraProperties
+}
+
+func (d *DnsResponseMetadata) UnmarshalJSON(data []byte) error {
+	type unmarshaler DnsResponseMetadata
+	var value unmarshaler
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*d = DnsResponseMetadata(value)
+
+	extraProperties, err := core.ExtractExtraProperties(data, *d)
+	if err != nil {
+		return err
+	}
+	d.extraProperties = extraProperties
+
+	d._rawJSON = json.RawMessage(data)
+	return nil
+}
+
+func (d *DnsResponseMetadata) String() string {
+	if len(d._rawJSON) > 0 {
+		if value, err := core.StringifyJSON(d._rawJSON); err == nil {
+			return value
+		}
+	}
+	if value, err := core.StringifyJSON(d); err == nil {
+		return value
+	}
+	return fmt.Sprintf("%#v", d)
+}
+
+type DnsSoaData struct {
+	Mname   string `json:"mname" url:"mname"`
+	Rname   string `json:"rname" url:"rname"`
+	Serial  int64  `json:"serial" url:"serial"`
+	Refresh int    `json:"refresh" url:"refresh"`
+	Retry   int    `json:"retry" url:"retry"`
+	Expire  int    `json:"expire" url:"expire"`
+	Minimum int    `json:"minimum" url:"minimum"`
+
+	extraProperties map[string]interface{}
+	_rawJSON        json.RawMessage
+}
+
+func (d *DnsSoaData) GetExtraProperties() map[string]interface{} {
+	return d.extraProperties
+}
+
+func (d *DnsSoaData) UnmarshalJSON(data []byte) error {
+	type unmarshaler DnsSoaData
+	var value unmarshaler
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*d = DnsSoaData(value)
+
+	extraProperties, err := core.ExtractExtraProperties(data, *d)
+	if err != nil {
+		return err
+	}
+	d.extraProperties = extraProperties
+
+	d._rawJSON = json.RawMessage(data)
+	return nil
+}
+
+func (d *DnsSoaData) String() string {
+	if len(d._rawJSON) > 0 {
+		if value, err := core.StringifyJSON(d._rawJSON); err == nil {
+			return value
+		}
+	}
+	if value, err := core.StringifyJSON(d); err == nil {
+		return value
+	}
+	return fmt.Sprintf("%#v", d)
+}
+
+type DnsSrvData struct {
+	Priority int    `json:"priority" url:"priority"`
+	Weight   int    `json:"weight" url:"weight"`
+	Port     int    `json:"port" url:"port"`
+	Target   string `json:"target" url:"target"`
+
+	extraProperties map[string]interface{}
+	_rawJSON        json.RawMessage
+}
+
+func (d *DnsSrvData) GetExtraProperties() map[string]interface{} {
+	return d.extraProperties
+}
+
+func (d *DnsSrvData) UnmarshalJSON(data []byte) error {
+	type unmarshaler DnsSrvData
+	var value unmarshaler
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*d = DnsSrvData(value)
+
+	extraProperties, err := core.ExtractExtraProperties(data, *d)
+	if err != nil {
+		return err
+	}
+	d.extraProperties = extraProperties
+
+	d._rawJSON = json.RawMessage(data)
+	return nil
+}
+
+func (d *DnsSrvData) String() string {
+	if len(d._rawJSON) > 0 {
+		if value, err := core.StringifyJSON(d._rawJSON); err == nil {
+			return value
+		}
+	}
+	if value, err := core.StringifyJSON(d); err == nil {
+		return value
+	}
+	return fmt.Sprintf("%#v", d)
+}
+
+type DnsSubdomainResolution struct {
+	Name       string              `json:"name" url:"name"`
+	Status     DnsResolutionStatus `json:"status" url:"status"`
+	Rcode      string              `json:"rcode" url:"rcode"`
+	Addresses  []string            `json:"addresses,omitempty" url:"addresses,omitempty"`
+	CnameChain []string            `json:"cnameChain,omitempty" url:"cnameChain,omitempty"`
+
+	extraProperties map[string]interface{}
+	_rawJSON        json.RawMessage
+}
+
+func (d *DnsSubdomainResolution) GetExtraProperties() map[string]interface{} {
+	return d.extraProperties
+}
+
+func (d *DnsSubdomainResolution) UnmarshalJSON(data []byte) error {
+	type unmarshaler DnsSubdomainResolution
+	var value unmarshaler
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*d = DnsSubdomainResolution(value)
+
+	extraProperties, err := core.ExtractExtraProperties(data, *d)
+	if err != nil {
+		return err
+	}
+	d.extraProperties = extraProperties
+
+	d._rawJSON = json.RawMessage(data)
+	return nil
+}
+
+func (d *DnsSubdomainResolution) String() string {
+	if len(d._rawJSON) > 0 {
+		if value, err := core.StringifyJSON(d._rawJSON); err == nil {
+			return value
+		}
+	}
+	if value, err := core.StringifyJSON(d); err == nil {
+		return value
+	}
+	return fmt.Sprintf("%#v", d)
+}
+
+type DnsSubenumReport struct {
+	Domain            string                    `json:"domain" url:"domain"`
+	EnumerationType   DnsSubenumType            `json:"enumerationType" url:"enumerationType"`
+	Subdomains        []string                  `json:"subdomains,omitempty" url:"subdomains,omitempty"`
+	SubdomainDetails  []*Subdomain              `json:"subdomainDetails,omitempty" url:"subdomainDetails,omitempty"`
+	Sources           map[string][]string       `json:"sources,omitempty" url:"sources,omitempty"`
+	Resolutions       []*DnsSubdomainResolution `json:"resolutions,omitempty" url:"resolutions,omitempty"`
+	NoAddressNames    []string                  `json:"noAddressNames,omitempty" url:"noAddressNames,omitempty"`
+	EmptyNonTerminals []string                  `json:"emptyNonTerminals,omitempty" url:"emptyNonTerminals,omitempty"`
+	DanglingCnames    []*DnsDanglingCname       `json:"danglingCnames,omitempty" url:"danglingCnames,omitempty"`
+	Wildcards         []*DnsWildcard            `json:"wildcards,omitempty" url:"wildcards,omitempty"`
+	Nsec3             *Nsec3Chain               `json:"nsec3,omitempty" url:"nsec3,omitempty"`
+	Errors            []string                  `json:"errors,omitempty" url:"errors,omitempty"`
+
+	extraProperties map[string]interface{}
+	_rawJSON        json.RawMessage
+}
+
+func (d *DnsSubenumReport) GetExtraProperties() map[string]interface{} {
+	return d.extraProperties
+}
+
+func (d *DnsSubenumReport) UnmarshalJSON(data []byte) error {
+	type unmarshaler DnsSubenumReport
+	var value unmarshaler
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*d = DnsSubenumReport(value)
+
+	extraProperties, err := core.ExtractExtraProperties(data, *d)
+	if err != nil {
+		return err
+	}
+	d.extraProperties = extraProperties
+
+	d._rawJSON = json.RawMessage(data)
+	return nil
+}
+
+func (d *DnsSubenumReport) String() string {
+	if len(d._rawJSON) > 0 {
+		if value, err := core.StringifyJSON(d._rawJSON); err == nil {
+			return value
+		}
+	}
+	if value, err := core.StringifyJSON(d); err == nil {
+		return value
+	}
+	return fmt.Sprintf("%#v", d)
+}
+
+type DnsSubenumType string
+
+const (
+	DnsSubenumTypeBrute   DnsSubenumType = "BRUTE"
+	DnsSubenumTypePassive DnsSubenumType = "PASSIVE"
+	DnsSubenumTypeWalk    DnsSubenumType = "WALK"
+	DnsSubenumTypePermute DnsSubenumType = "PERMUTE"
+	DnsSubenumTypeAll     DnsSubenumType = "ALL"
+)
+
+func NewDnsSubenumTypeFromString(s string) (DnsSubenumType, error) {
+	switch s {
+	case "BRUTE":
+		return DnsSubenumTypeBrute, nil
+	case "PASSIVE":
+		return DnsSubenumTypePassive, nil
+	case "WALK":
+		return DnsSubenumTypeWalk, nil
+	case "PERMUTE":
+		return DnsSubenumTypePermute, nil
+	case "ALL":
+		return DnsSubenumTypeAll, nil
+	}
+	var t DnsSubenumType
+	return "", fmt.Errorf("%s is not a valid %T", s, t)
+}
+
+func (d DnsSubenumType) Ptr() *DnsSubenumType {
+	return &d
+}
+
+type DnsSvcbData struct {
+	Priority int               `json:"priority" url:"priority"`
+	Target   string            `json:"target" url:"target"`
+	Params   map[string]string `json:"params,omitempty" url:"params,omitempty"`
+
+	extraProperties map[string]interface{}
+	_rawJSON        json.RawMessage
+}
+
+func (d *DnsSvcbData) GetExtraProperties() map[string]interface{} {
+	return d.extraProperties
+}
+
+func (d *DnsSvcbData) UnmarshalJSON(data []byte) error {
+	type unmarshaler DnsSvcbData
+	var value unmarshaler
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*d = DnsSvcbData(value)
+
+	extraProperties, err := core.ExtractExtraProperties(data, *d)
+	if err != nil {
+		return err
+	}
+	d.extraProperties = extraProperties
+
+	d._rawJSON = json.RawMessage(data)
+	return nil
+}
+
+func (d *DnsSvcbData) String() string {
+	if len(d._rawJSON) > 0 {
+		if value, err := core.StringifyJSON(d._rawJSON); err == nil {
+			return value
+		}
+	}
+	if value, err := core.StringifyJSON(d); err == nil {
+		return value
+	}
+	return fmt.Sprintf("%#v", d)
+}
+
+type DnsTlsaData struct {
+	Usage        int    `json:"usage" url:"usage"`
+	Selector     int    `json:"selector" url:"selector"`
+	MatchingType int    `json:"matchingType" url:"matchingType"`
+	Certificate  string `json:"certificate" url:"certificate"`
+
+	extraProperties map[string]interface{}
+	_rawJSON        json.RawMessage
+}
+
+func (d *DnsTlsaData) GetExtraProperties() map[string]interface{} {
+	return d.extraProperties
+}
+
+func (d *DnsTlsaData) UnmarshalJSON(data []byte) error {
+	type unmarshaler DnsTlsaData
+	var value unmarshaler
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*d = DnsTlsaData(value)
+
+	extraProperties, err := core.ExtractExtraProperties(data, *d)
+	if err != nil {
+		return err
+	}
+	d.extraProperties = extraProperties
+
+	d._rawJSON = json.RawMessage(data)
+	return nil
+}
+
+func (d *DnsTlsaData) String() string {
+	if len(d._rawJSON) > 0 {
+		if value, err := core.StringifyJSON(d._rawJSON); err == nil {
+			return value
+		}
+	}
+	if value, err := core.StringifyJSON(d); err == nil {
+		return value
+	}
+	return fmt.Sprintf("%#v", d)
+}
+
+type DnsTraceCnameHop struct {
+	Name   string `json:"name" url:"name"`
+	Target string `json:"target" url:"target"`
+	Ttl    int    `json:"ttl" url:"ttl"`
+
+	extraProperties map[string]interface{}
+	_rawJSON        json.RawMessage
+}
+
+func (d *DnsTraceCnameHop) GetExtraProperties() map[string]interface{} {
+	return d.extraProperties
+}
+
+func (d *DnsTraceCnameHop) UnmarshalJSON(data []byte) error {
+	type unmarshaler DnsTraceCnameHop
+	var value unmarshaler
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*d = DnsTraceCnameHop(value)
+
+	extraProperties, err := core.ExtractExtraProperties(data, *d)
+	if err != nil {
+		return err
+	}
+	d.extraProperties = extraProperties
+
+	d._rawJSON = json.RawMessage(data)
+	return nil
+}
+
+func (d *DnsTraceCnameHop) String() string {
+	if len(d._rawJSON) > 0 {
+		if value, err := core.StringifyJSON(d._rawJSON); err == nil {
+			return value
+		}
+	}
+	if value, err := core.StringifyJSON(d); err == nil {
+		return value
+	}
+	return fmt.Sprintf("%#v", d)
+}
+
+type DnsTraceReport struct {
+	Name        string              `json:"name" url:"name"`
+	Type        string              `json:"type" url:"type"`
+	Rcode       *string             `json:"rcode,omitempty" url:"rcode,omitempty"`
+	Steps       []*DnsTraceStep     `json:"steps,omitempty" url:"steps,omitempty"`
+	CnameChain  []*DnsTraceCnameHop `json:"cnameChain,omitempty" url:"cnameChain,omitempty"`
+	Answers     []*DnsRecord        `json:"answers,omitempty" url:"answers,omitempty"`
+	TotalTimeMs int                 `json:"totalTimeMs" url:"totalTimeMs"`
+	Errors      []string            `json:"errors,omitempty" url:"errors,omitempty"`
+
+	extraProperties map[string]interface{}
+	_rawJSON        json.RawMessage
+}
+
+func (d *DnsTraceReport) GetExtraProperties() map[string]interface{} {
+	return d.extraProperties
+}
+
+func (d *DnsTraceReport) UnmarshalJSON(data []byte) error {
+	type unmarshaler DnsTraceReport
+	var value unmarshaler
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*d = DnsTraceReport(value)
+
+	extraProperties, err := core.ExtractExtraProperties(data, *d)
+	if err != nil {
+		return err
+	}
+	d.extraProperties = extraProperties
+
+	d._rawJSON = json.RawMessage(data)
+	return nil
+}
+
+func (d *DnsTraceReport) String() string {
+	if len(d._rawJSON) > 0 {
+		if value, err := core.StringifyJSON(d._rawJSON); err == nil {
+			return value
+		}
+	}
+	if value, err := core.StringifyJSON(d); err == nil {
+		return value
+	}
+	return fmt.Sprintf("%#v", d)
+}
+
+type DnsTraceStep struct {
+	Zone          string       `json:"zone" url:"zone"`
+	Name          string       `json:"name" url:"name"`
+	Type          string       `json:"type" url:"type"`
+	Server        string       `json:"server" url:"server"`
+	Address       string       `json:"address" url:"address"`
+	Rcode         *string      `json:"rcode,omitempty" url:"rcode,omitempty"`
+	Authoritative bool         `json:"authoritative" url:"authoritative"`
+	QueryTimeMs   int          `json:"queryTimeMs" url:"queryTimeMs"`
+	ReferralZone  *string      `json:"referralZone,omitempty" url:"referralZone,omitempty"`
+	Referral      []string     `json:"referral,omitempty" url:"referral,omitempty"`
+	Glue          []*DnsRecord `json:"glue,omitempty" url:"glue,omitempty"`
+	Answers       []*DnsRecord `json:"answers,omitempty" url:"answers,omitempty"`
+	Error         *string      `json:"error,omitempty" url:"error,omitempty"`
+
+	extraProperties map[string]interface{}
+	_rawJSON        json.RawMessage
+}
+
+func (d *DnsTraceStep) GetExtraProperties() map[string]interface{} {
+	return d.extraProperties
+}
+
+func (d *DnsTraceStep) UnmarshalJSON(data []byte) error {
+	type unmarshaler DnsTraceStep
+	var value unmarshaler
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*d = DnsTraceStep(value)
+
+	extraProperties, err := core.ExtractExtraProperties(data, *d)
+	if err != nil {
+		return err
+	}
+	d.extraProperties = extraProperties
+
+	d._rawJSON = json.RawMessage(data)
+	return nil
+}
+
+func (d *DnsTraceStep) String() string {
+	if len(d._rawJSON) > 0 {
+		if value, err := core.StringifyJSON(d._rawJSON); err == nil {
+			return value
+		}
+	}
+	if value, err := core.StringifyJSON(d); err == nil {
+		return value
+	}
+	return fmt.Sprintf("%#v", d)
+}
+
+type DnsWildcard struct {
+	Domain    string   `json:"domain" url:"domain"`
+	Addresses []string `json:"addresses,omitempty" url:"addresses,omitempty"`
+	Cname     *string  `json:"cname,omitempty" url:"cname,omitempty"`
+
+	extraProperties map[string]interface{}
+	_rawJSON        json.RawMessage
+}
+
+func (d *DnsWildcard) GetExtraProperties() map[string]interface{} {
+	return d.extraProperties
+}
+
+func (d *DnsWildcard) UnmarshalJSON(data []byte) error {
+	type unmarshaler DnsWildcard
+	var value unmarshaler
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*d = DnsWildcard(value)
+
+	extraProperties, err := core.ExtractExtraProperties(data, *d)
+	if err != nil {
+		return err
+	}
+	d.extraProperties = extraProperties
+
+	d._rawJSON = json.RawMessage(data)
+	return nil
+}
+
+func (d *DnsWildcard) String() string {
+	if len(d._rawJSON) > 0 {
+		if value, err := core.StringifyJSON(d._rawJSON); err == nil {
+			return value
+		}
+	}
+	if value, err := core.StringifyJSON(d); err == nil {
+		return value
+	}
+	return fmt.Sprintf("%#v", d)
+}
+
+type DnsWordlist struct {
+	Name    string `json:"name" url:"name"`
+	Entries int    `json:"entries" url:"entries"`
+
+	extraProperties map[string]interface{}
+	_rawJSON        json.RawMessage
+}
+
+func (d *DnsWordlist) GetExtraProperties() map[string]interface{} {
+	return d.extraProperties
+}
+
+func (d *DnsWordlist) UnmarshalJSON(data []byte) error {
+	type unmarshaler DnsWordlist
+	var value unmarshaler
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*d = DnsWordlist(value)
+
+	extraProperties, err := core.ExtractExtraProperties(data, *d)
+	if err != nil {
+		return err
+	}
+	d.extraProperties = extraProperties
+
+	d._rawJSON = json.RawMessage(data)
+	return nil
+}
+
+func (d *DnsWordlist) String() string {
+	if len(d._rawJSON) > 0 {
+		if value, err := core.StringifyJSON(d._rawJSON); err == nil {
+			return value
+		}
+	}
+	if value, err := core.StringifyJSON(d); err == nil {
+		return value
+	}
+	return fmt.Sprintf("%#v", d)
+}
+
+type DnsWordlistsReport struct {
+	Wordlists    []*DnsWordlist `json:"wordlists,omitempty" url:"wordlists,omitempty"`
+	ExportedFile *string        `json:"exportedFile,omitempty" url:"exportedFile,omitempty"`
+	Errors       []string       `json:"errors,omitempty" url:"errors,omitempty"`
+
+	extraProperties map[string]interface{}
+	_rawJSON        json.RawMessage
+}
+
+func (d *DnsWordlistsReport) GetExtraProperties() map[string]interface{} {
+	return d.extraProperties
+}
+
+func (d *DnsWordlistsReport) UnmarshalJSON(data []byte) error {
+	type unmarshaler DnsWordlistsReport
+	var value unmarshaler
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*d = DnsWordlistsReport(value)
+
+	extraProperties, err := core.ExtractExtraProperties(data, *d)
+	if err != nil {
+		return err
+	}
+	d.extraProperties = extraProperties
+
+	d._rawJSON = json.RawMessage(data)
+	return nil
+}
+
+func (d *DnsWordlistsReport) String() string {
+	if len(d._rawJSON) > 0 {
+		if value, err := core.StringifyJSON(d._rawJSON); err == nil {
+			return value
+		}
+	}
+	if value, err := core.StringifyJSON(d); err == nil {
+		return value
+	}
+	return fmt.Sprintf("%#v", d)
+}
+
+type DnssecDenial struct {
+	Type               DnssecDenialType `json:"type" url:"type"`
+	Nsec3HashAlgorithm *int             `json:"nsec3HashAlgorithm,omitempty" url:"nsec3HashAlgorithm,omitempty"`
+	Nsec3Iterations    *int             `json:"nsec3Iterations,omitempty" url:"nsec3Iterations,omitempty"`
+	Nsec3Salt          *string          `json:"nsec3Salt,omitempty" url:"nsec3Salt,omitempty"`
+	Nsec3OptOut        *bool            `json:"nsec3OptOut,omitempty" url:"nsec3OptOut,omitempty"`
+
+	extraProperties map[string]interface{}
+	_rawJSON        json.RawMessage
+}
+
+func (d *DnssecDenial) GetExtraProperties() map[string]interface{} {
+	return d.extraProperties
+}
+
+func (d *DnssecDenial) UnmarshalJSON(data []byte) error {
+	type unmarshaler DnssecDenial
+	var value unmarshaler
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*d = DnssecDenial(value)
+
+	extraProperties, err := core.ExtractExtraProperties(data, *d)
+	if err != nil {
+		return err
+	}
+	d.extraProperties = extraProperties
+
+	d._rawJSON = json.RawMessage(data)
+	return nil
+}
+
+func (d *DnssecDenial) String() string {
+	if len(d._rawJSON) > 0 {
+		if value, err := core.StringifyJSON(d._rawJSON); err == nil {
+			return value
+		}
+	}
+	if value, err := core.StringifyJSON(d); err == nil {
+		return value
+	}
+	return fmt.Sprintf("%#v", d)
+}
+
+type DnssecDenialType string
+
+const (
+	DnssecDenialTypeNsec  DnssecDenialType = "NSEC"
+	DnssecDenialTypeNsec3 DnssecDenialType = "NSEC3"
+)
+
+func NewDnssecDenialTypeFromString(s string) (DnssecDenialType, error) {
+	switch s {
+	case "NSEC":
+		return DnssecDenialTypeNsec, nil
+	case "NSEC3":
+		return DnssecDenialTypeNsec3, nil
+	}
+	var t DnssecDenialType
+	return "", fmt.Errorf("%s is not a valid %T", s, t)
+}
+
+func (d DnssecDenialType) Ptr() *DnssecDenialType {
+	return &d
+}
+
+type DnssecKey struct {
+	KeyTag           int    `json:"keyTag" url:"keyTag"`
+	Flags            int    `json:"flags" url:"flags"`
+	Algorithm        int    `json:"algorithm" url:"algorithm"`
+	AlgorithmName    string `json:"algorithmName" url:"algorithmName"`
+	KeySize          *int   `json:"keySize,omitempty" url:"keySize,omitempty"`
+	SecureEntryPoint bool   `json:"secureEntryPoint" url:"secureEntryPoint"`
+	Revoked          bool   `json:"revoked" url:"revoked"`
+	Trusted          bool   `json:"trusted" url:"trusted"`
+
+	extraProperties map[string]interface{}
+	_rawJSON        json.RawMessage
+}
+
+func (d *DnssecKey) GetExtraProperties() map[string]interface{} {
+	return d.extraProperties
+}
+
+func (d *DnssecKey) UnmarshalJSON(data []byte) error {
+	type unmarshaler DnssecKey
+	var value unmarshaler
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*d = DnssecKey(value)
+
+	extraProperties, err := core.ExtractExtraProperties(data, *d)
+	if err != nil {
+		return err
+	}
+	d.extraProperties = extraProperties
+
+	d._rawJSON = json.RawMessage(data)
+	return nil
+}
+
+func (d *DnssecKey) String() string {
+	if len(d._rawJSON) > 0 {
+		if value, err := core.StringifyJSON(d._rawJSON); err == nil {
+			return value
+		}
+	}
+	if value, err := core.StringifyJSON(d); err == nil {
+		return value
+	}
+	return fmt.Sprintf("%#v", d)
+}
+
+type DnssecReport struct {
+	Domain     string        `json:"domain" url:"domain"`
+	Status     DnssecStatus  `json:"status" url:"status"`
+	BrokenLink *string       `json:"brokenLink,omitempty" url:"brokenLink,omitempty"`
+	Zones      []*DnssecZone `json:"zones,omitempty" url:"zones,omitempty"`
+	Errors     []string      `json:"errors,omitempty" url:"errors,omitempty"`
+
+	extraProperties map[string]interface{}
+	_rawJSON        json.RawMessage
+}
+
+func (d *DnssecReport) GetExtraProperties() map[string]interface{} {
+	return d.extraProperties
+}
+
+func (d *DnssecReport) UnmarshalJSON(data []byte) error {
+	type unmarshaler DnssecReport
+	var value unmarshaler
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*d = DnssecReport(value)
+
+	extraProperties, err := core.ExtractExtraProperties(data, *d)
+	if err != nil {
+		return err
+	}
+	d.extraProperties = extraProperties
+
+	d._rawJSON = json.RawMessage(data)
+	return nil
+}
+
+func (d *DnssecReport) String() string {
+	if len(d._rawJSON) > 0 {
+		if value, err := core.StringifyJSON(d._rawJSON); err == nil {
+			return value
+		}
+	}
+	if value, err := core.StringifyJSON(d); err == nil {
+		return value
+	}
+	return fmt.Sprintf("%#v", d)
+}
+
+type DnssecSignature struct {
+	Name          string    `json:"name" url:"name"`
+	TypeCovered   string    `json:"typeCovered" url:"typeCovered"`
+	KeyTag        int       `json:"keyTag" url:"keyTag"`
+	SignerName    string    `json:"signerName" url:"signerName"`
+	Algorithm     int       `json:"algorithm" url:"algorithm"`
+	AlgorithmName string    `json:"algorithmName" url:"algorithmName"`
+	Inception     time.Time `json:"inception" url:"inception"`
+	Expiration    time.Time `json:"expiration" url:"expiration"`
+	Valid         bool      `json:"valid" url:"valid"`
+	Error         *string   `json:"error,omitempty" url:"error,omitempty"`
+
+	extraProperties map[string]interface{}
+	_rawJSON        json.RawMessage
+}
+
+func (d *DnssecSignature) GetExtraProperties() map[string]interface{} {
+	return d.extraProperties
+}
+
+func (d *DnssecSignature) UnmarshalJSON(data []byte) error {
+	type embed DnssecSignature
+	var unmarshaler = struct {
+		embed
+		Inception  *core.DateTime `json:"inception"`
+		Expiration *core.DateTime `json:"expiration"`
+	}{
+		embed: embed(*d),
+	}
+	if err := json.Unmarshal(data, &unmarshaler); err != nil {
+		return err
+	}
+	*d = DnssecSignature(unmarshaler.embed)
+	d.Inception = unmarshaler.Inception.Time()
+	d.Expiration = unmarshaler.Expiration.Time()
+
+	extraProperties, err := core.ExtractExtraProperties(data, *d)
+	if err != nil {
+		return err
+	}
+	d.extraProperties = extraProperties
+
+	d._rawJSON = json.RawMessage(data)
+	return nil
+}
+
+func (d *DnssecSignature) MarshalJSON() ([]byte, error) {
+	type embed DnssecSignature
+	var marshaler = struct {
+		embed
+		Inception  *core.DateTime `json:"inception"`
+		Expiration *core.DateTime `json:"expiration"`
+	}{
+		embed:      embed(*d),
+		Inception:  core.NewDateTime(d.Inception),
+		Expiration: core.NewDateTime(d.Expiration),
+	}
+	return json.Marshal(marshaler)
+}
+
+func (d *DnssecSignature) String() string {
+	if len(d._rawJSON) > 0 {
+		if value, err := core.StringifyJSON(d._rawJSON); err == nil {
+			return value
+		}
+	}
+	if value, err := core.StringifyJSON(d); err == nil {
+		return value
+	}
+	return fmt.Sprintf("%#v", d)
+}
+
+type DnssecStatus string
+
+const (
+	DnssecStatusSecure   DnssecStatus = "SECURE"
+	DnssecStatusInsecure DnssecStatus = "INSECURE"
+	DnssecStatusBogus    DnssecStatus = "BOGUS"
+)
+
+func NewDnssecStatusFromString(s string) (DnssecStatus, error) {
+	switch s {
+	case "SECURE":
+		return DnssecStatusSecure, nil
+	case "INSECURE":
+		return DnssecStatusInsecure, nil
+	case "BOGUS":
+		return DnssecStatusBogus, nil
+	}
+	var t DnssecStatus
+	return "", fmt.Errorf("%s is not a valid %T", s, t)
+}
+
+func (d DnssecStatus) Ptr() *DnssecStatus {
+	return &d
+}
+
+type DnssecZone struct {
+	Zone       string             `json:"zone" url:"zone"`
+	Status     DnssecStatus       `json:"status" url:"status"`
+	Ds         []*DnsDsData       `json:"ds,omitempty" url:"ds,omitempty"`
+	Keys       []*DnssecKey       `json:"keys,omitempty" url:"keys,omitempty"`
+	Signatures []*DnssecSignature `json:"signatures,omitempty" url:"signatures,omitempty"`
+	Denial     *DnssecDenial      `json:"denial,omitempty" url:"denial,omitempty"`
+	Findings   []string           `json:"findings,omitempty" url:"findings,omitempty"`
+
+	extraProperties map[string]interface{}
+	_rawJSON        json.RawMessage
+}
+
+func (d *DnssecZone) GetExtraProperties() map[string]interface{} {
+	return d.extraProperties
+}
+
+func (d *DnssecZone) UnmarshalJSON(data []byte) error {
+	type unmarshaler DnssecZone
+	var value unmarshaler
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*d = DnssecZone(value)
+
+	extraProperties, err := core.ExtractExtraProperties(data, *d)
+	if err != nil {
+		return err
+	}
+	d.extraProperties = extraProperties
+
+	d._rawJSON = json.RawMessage(data)
+	return nil
+}
+
+func (d *DnssecZone) String() string {
+	if len(d._rawJSON) > 0 {
+		if value, err := core.StringifyJSON(d._rawJSON); err == nil {
+			return value
+		}
+	}
+	if value, err := core.StringifyJSON(d); err == nil {
+		return value
+	}
+	return fmt.Sprintf("%#v", d)
+}
+
+type DomainTakeover struct {
+	Target       string     `json:"target" url:"target"`
+	StatusCode   int        `json:"statusCode" url:"statusCode"`
+	ResponseBody string     `json:"responseBody" url:"responseBody"`
+	Domain       string     `json:"domain" url:"domain"`
+	Cname        string     `json:"cname" url:"cname"`
+	Services     []*Service `json:"services,omitempty" url:"services,omitempty"`
+
+	extraProperties map[string]interface{}
+	_rawJSON        json.RawMessage
+}
+
+func (d *DomainTakeover) GetExtraProperties() map[string]interface{} {
+	return d.extraProperties
+}
+
+func (d *DomainTakeover) UnmarshalJSON(data []byte) error {
+	type unmarshaler DomainTakeover
+	var value unmarshaler
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*d = DomainTakeover(value)
+
+	extraProperties, err := core.ExtractExtraProperties(data, *d)
+	if err != nil {
+		return err
+	}
+	d.extraProperties = extraProperties
+
+	d._rawJSON = json.RawMessage(data)
+	return nil
+}
+
+func (d *DomainTakeover) String() string {
+	if len(d._rawJSON) > 0 {
+		if value, err := core.StringifyJSON(d._rawJSON); err == nil {
+			return value
+		}
+	}
+	if value, err := core.StringifyJSON(d); err == nil {
+		return value
+	}
+	return fmt.Sprintf("%#v", d)
+}
+
+type DomainTakeoverReport struct {
+	DomainTakeovers []*DomainTakeover `json:"domainTakeovers,omitempty" url:"domainTakeovers,omitempty"`
+	Errors          []string          `json:"errors,omitempty" url:"errors,omitempty"`
+
+	extraProperties map[string]interface{}
+	_rawJSON        json.RawMessage
+}
+
+func (d *DomainTakeoverReport) GetExtraProperties() map[string]interface{} {
+	return d.extraProperties
+}
+
+func (d *DomainTakeoverReport) UnmarshalJSON(data []byte) error {
+	type unmarshaler DomainTakeoverReport
+	var value unmarshaler
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*d = DomainTakeoverReport(value)
+
+	extraProperties, err := core.ExtractExtraProperties(data, *d)
+	if err != nil {
+		return err
+	}
+	d.extraProperties = extraProperties
+
+	d._rawJSON = json.RawMessage(data)
+	return nil
+}
+
+func (d *DomainTakeoverReport) String() string {
+	if len(d._rawJSON) > 0 {
+		if value, err := core.StringifyJSON(d._rawJSON); err == nil {
+			return value
+		}
+	}
+	if value, err := core.StringifyJSON(d); err == nil {
+		return value
+	}
+	return fmt.Sprintf("%#v", d)
+}
+
+type EmailControlStatus string
+
+const (
+	EmailControlStatusPass    EmailControlStatus = "PASS"
+	EmailControlStatusFail    EmailControlStatus = "FAIL"
+	EmailControlStatusMissing EmailControlStatus = "MISSING"
+)
+
+func NewEmailControlStatusFromString(s string) (EmailControlStatus, error) {
+	switch s {
+	case "PASS":
+		return EmailControlStatusPass, nil
+	case "FAIL":
+		return EmailControlStatusFail, nil
+	case "MISSING":
+		return EmailControlStatusMissing, nil
+	}
+	var t EmailControlStatus
+	return "", fmt.Errorf("%s is not a valid %T", s, t)
+}
+
+func (e EmailControlStatus) Ptr() *EmailControlStatus {
+	return &e
+}
+
+type EmailSecurityReport struct {
+	Domain string        `json:"domain" url:"domain"`
+	MtaSts *MtaStsPolicy `json:"mtaSts,omitempty" url:"mtaSts,omitempty"`
+	TlsRpt *TlsRptPolicy `json:"tlsRpt,omitempty" url:"tlsRpt,omitempty"`
+	Bimi   *BimiRecord   `json:"bimi,omitempty" url:"bimi,omitempty"`
+	Dane   *DanePolicy   `json:"dane,omitempty" url:"dane,omitempty"`
+	Errors []string      `json:"errors,omitempty" url:"errors,omitempty"`
+
+	extraProperties map[string]interface{}
+	_rawJSON        json.RawMessage
+}
+
+func (e *EmailSecurityReport) GetExtraProperties() map[string]interface{} {
+	return e.extraProperties
+}
+
+func (e *EmailSecurityReport) UnmarshalJSON(data []byte) error {
+	type unmarshaler EmailSecurityReport
+	var value unmarshaler
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*e = EmailSecurityReport(value)
+
+	extraProperties, err := core.ExtractExtraProperties(data, *e)
+	if err != nil {
+		return err
+	}
+	e.extraProperties = extraProperties
+
+	e._rawJSON = json.RawMessage(data)
+	return nil
+}
+
+func (e *EmailSecurityReport) String() string {
+	if len(e._rawJSON) > 0 {
+		if value, err := core.StringifyJSON(e._rawJSON); err == nil {
+			return value
+		}
+	}
+	if value, err := core.StringifyJSON(e); err == nil {
+		return value
+	}
+	return fmt.Sprintf("%#v", e)
+}
+
+type Fingerprint struct {
+	CicdPass      bool     `json:"cicdPass" url:"cicdPass"`
+	Cname         []string `json:"cname,omitempty" url:"cname,omitempty"`
+	Discussion    string   `json:"discussion" url:"discussion"`
+	Documentation string   `json:"documentation" url:"documentation"`
+	Fingerprint   string   `json:"fingerprint" url:"fingerprint"`
+	HttpStatus    *int     `json:"httpStatus,omitempty" url:"httpStatus,omitempty"`
+	NxDomain      bool     `json:"nxDomain" url:"nxDomain"`
+	Service       string   `json:"service" url:"service"`
+	Status        string   `json:"status" url:"status"`
+	Vulnerable    bool     `json:"vulnerable" url:"vulnerable"`
+
+	extraProperties map[string]interface{}
+	_rawJSON        json.RawMessage
+}
+
+func (f *Fingerprint) GetExtraProperties() map[string]interface{} {
+	return f.extraProperties
+}
+
+func (f *Fingerprint) UnmarshalJSON(data []byte) error {
+	type unmarshaler Fingerprint
+	var value unmarshaler
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*f = Fingerprint(value)
+
+	extraProperties, err := core.ExtractExtraProperties(data, *f)
+	if err != nil {
+		return err
+	}
+	f.extraProperties = extraProperties
+
+	f._rawJSON = json.RawMessage(data)
+	return nil
+}
+
+func (f *Fingerprint) String() string {
+	if len(f._rawJSON) > 0 {
+		if value, err := core.StringifyJSON(f._rawJSON); err == nil {
+			return value
+		}
+	}
+	if value, err := core.StringifyJSON(f); err == nil {
+		return value
+	}
+	return fmt.Sprintf("%#v", f)
+}
+
+type MtaStsPolicy struct {
+	Status    EmailControlStatus `json:"status" url:"status"`
+	Record    *string            `json:"record,omitempty" url:"record,omitempty"`
+	Id        *string            `json:"id,omitempty" url:"id,omitempty"`
+	PolicyUrl string             `json:"policyUrl" url:"policyUrl"`
+	Policy    *string            `json:"policy,omitempty" url:"policy,omitempty"`
+	Version   *string            `json:"version,omitempty" url:"version,omitempty"`
+	Mode      *string            `json:"mode,omitempty" url:"mode,omitempty"`
+	Mx        []string           `json:"mx,omitempty" url:"mx,omitempty"`
+	MaxAge    *int               `json:"maxAge,omitempty" url:"maxAge,omitempty"`
+	Findings  []string           `json:"findings,omitempty" url:"findings,omitempty"`
+
+	extraProperties map[string]interface{}
+	_rawJSON        json.RawMessage
+}
+
+func (m *MtaStsPolicy) GetExtraProperties() map[string]interface{} {
+	return m.extraProperties
+}
+
+func (m *MtaStsPolicy) UnmarshalJSON(data []byte) error {
+	type unmarshaler MtaStsPolicy
+	var value unmarshaler
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*m = MtaStsPolicy(value)
+
+	extraProperties, err := core.ExtractExtraProperties(data, *m)
+	if err != nil {
+		return err
+	}
+	m.extraProperties = extraProperties
+
+	m._rawJSON = json.RawMessage(data)
+	return nil
+}
+
+func (m *MtaStsPolicy) String() string {
+	if len(m._rawJSON) > 0 {
+		if value, err := core.StringifyJSON(m._rawJSON); err == nil {
+			return value
+		}
+	}
+	if value, err := core.StringifyJSON(m); err == nil {
+		return value
+	}
+	return fmt.Sprintf("%#v", m)
+}
+
+type Nsec3Chain struct {
+	Zone          string       `json:"zone" url:"zone"`
+	HashAlgorithm int          `json:"hashAlgorithm" url:"hashAlgorithm"`
+	Iterations    int          `json:"iterations" url:"iterations"`
+	Salt          string       `json:"salt" url:"salt"`
+	OptOut        bool         `json:"optOut" url:"optOut"`
+	Complete      bool         `json:"complete" url:"complete"`
+	Hashes        []*Nsec3Hash `json:"hashes,omitempty" url:"hashes,omitempty"`
+
+	extraProperties map[string]interface{}
+	_rawJSON        json.RawMessage
+}
+
+func (n *Nsec3Chain) GetExtraProperties() map[string]interface{} {
+	return n.extraProperties
+}
+
+func (n *Nsec3Chain) UnmarshalJSON(data []byte) error {
+	type unmarshaler Nsec3Chain
+	var value unmarshaler
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*n = Nsec3Chain(value)
+
+	extraProperties, err := core.ExtractExtraProperties(data, *n)
+	if err != nil {
+		return err
+	}
+	n.extraProperties = extraProperties
+
+	n._rawJSON = json.RawMessage(data)
+	return nil
+}
+
+func (n *Nsec3Chain) String() string {
+	if len(n._rawJSON) > 0 {
+		if value, err := core.StringifyJSON(n._rawJSON); err == nil {
+			return value
+		}
+	}
+	if value, err := core.StringifyJSON(n); err == nil {
+		return value
+	}
+	return fmt.Sprintf("%#v", n)
+}
+
+type Nsec3Hash struct {
+	Hash     string   `json:"hash" url:"hash"`
+	NextHash string   `json:"nextHash" url:"nextHash"`
+	Types    []string `json:"types,omitempty" url:"types,omitempty"`
+
+	extraProperties map[string]interface{}
+	_rawJSON        json.RawMessage
+}
+
+func (n *Nsec3Hash) GetExtraProperties() map[string]interface{} {
+	return n.extraProperties
+}
+
+func (n *Nsec3Hash) UnmarshalJSON(data []byte) error {
+	type unmarshaler Nsec3Hash
+	var value unmarshaler
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*n = Nsec3Hash(value)
+
+	extraProperties, err := core.ExtractExtraProperties(data, *n)
+	if err != nil {
+		return err
+	}
+	n.extraProperties = extraProperties
+
+	n._rawJSON = json.RawMessage(data)
+	return nil
+}
+
+func (n *Nsec3Hash) String() string {
+	if len(n._rawJSON) > 0 {
+		if value, err := core.StringifyJSON(n._rawJSON); err == nil {
+			return value
+		}
+	}
+	if value, err := core.StringifyJSON(n); err == nil {
+		return value
+	}
+	return fmt.Sprintf("%#v", n)
+}
+
+type PtrRecord struct {
+	Address   string   `json:"address" url:"address"`
+	Hostnames []string `json:"hostnames,omitempty" url:"hostnames,omitempty"`
+	Ttl       int      `json:"ttl" url:"ttl"`
+
+	extraProperties map[string]interface{}
+	_rawJSON        json.RawMessage
+}
+
+func (p *PtrRecord) GetExtraProperties() map[string]interface{} {
+	return p.extraProperties
+}
+
+func (p *PtrRecord) UnmarshalJSON(data []byte) error {
+	type unmarshaler PtrRecord
+	var value unmarshaler
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*p = PtrRecord(value)
+
+	extraProperties, err := core.ExtractExtraProperties(data, *p)
+	if err != nil {
+		return err
+	}
+	p.extraProperties = extraProperties
+
+	p._rawJSON = json.RawMessage(data)
+	return nil
+}
+
+func (p *PtrRecord) String() string {
+	if len(p._rawJSON) > 0 {
+		if value, err := core.StringifyJSON(p._rawJSON); err == nil {
+			return value
+		}
+	}
+	if value, err := core.StringifyJSON(p); err == nil {
+		return value
+	}
+	return fmt.Sprintf("%#v", p)
+}
+
+type PtrSweepReport struct {
+	Cidrs        []string     `json:"cidrs,omitempty" url:"cidrs,omitempty"`
+	Asns         []string     `json:"asns,omitempty" url:"asns,omitempty"`
+	Suffixes     []string     `json:"suffixes,omitempty" url:"suffixes,omitempty"`
+	AddressCount int          `json:"addressCount" url:"addressCount"`
+	Records      []*PtrRecord `json:"records,omitempty" url:"records,omitempty"`
+	Hostnames    []string     `json:"hostnames,omitempty" url:"hostnames,omitempty"`
+	Errors       []string     `json:"errors,omitempty" url:"errors,omitempty"`
+
+	extraProperties map[string]interface{}
+	_rawJSON        json.RawMessage
+}
+
+func (p *PtrSweepReport) GetExtraProperties() map[string]interface{} {
+	return p.extraProperties
+}
+
+func (p *PtrSweepReport) UnmarshalJSON(data []byte) error {
+	type unmarshaler PtrSweepReport
+	var value unmarshaler
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*p = PtrSweepReport(value)
+
+	extraProperties, err := core.ExtractExtraProperties(data, *p)
+	if err != nil {
+		return err
+	}
+	p.extraProperties = extraProperties
+
+	p._rawJSON = json.RawMessage(data)
+	return nil
+}
+
+func (p *PtrSweepReport) String() string {
+	if len(p._rawJSON) > 0 {
+		if value, err := core.StringifyJSON(p._rawJSON); err == nil {
+			return value
+		}
+	}
+	if value, err := core.StringifyJSON(p); err == nil {
+		return value
+	}
+	return fmt.Sprintf("%#v", p)
+}
+
+type Service struct {
+	Name        string `json:"name" url:"name"`
+	Fingerprint string `json:"fingerprint" url:"fingerprint"`
+	Vulnerable  bool   `json:"vulnerable" url:"vulnerable"`
+
+	extraProperties map[string]interface{}
+	_rawJSON        json.RawMessage
+}
+
+func (s *Service) GetExtraProperties() map[string]interface{} {
+	return s.extraProperties
+}
+
+func (s *Service) UnmarshalJSON(data []byte) error {
+	type unmarshaler Service
+	var value unmarshaler
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*s = Service(value)
+
+	extraProperties, err := core.ExtractExtraProperties(data, *s)
+	if err != nil {
+		return err
+	}
+	s.extraProperties = extraProperties
+
+	s._rawJSON = json.RawMessage(data)
+	return nil
+}
+
+func (s *Service) String() string {
+	if len(s._rawJSON) > 0 {
+		if value, err := core.StringifyJSON(s._rawJSON); err == nil {
+			return value
+		}
+	}
+	if value, err := core.StringifyJSON(s); err == nil {
+		return value
+	}
+	return fmt.Sprintf("%#v", s)
+}
+
+type SpfMechanism struct {
+	Qualifier    SpfQualifier `json:"qualifier" url:"qualifier"`
+	Name         string       `json:"name" url:"name"`
+	Value        *string      `json:"value,omitempty" url:"value,omitempty"`
+	CountsLookup bool         `json:"countsLookup" url:"countsLookup"`
+	VoidLookup   bool         `json:"voidLookup" url:"voidLookup"`
+	Include      *SpfRecord   `json:"include,omitempty" url:"include,omitempty"`
+
+	extraProperties map[string]interface{}
+	_rawJSON        json.RawMessage
+}
+
+func (s *SpfMechanism) GetExtraProperties() map[string]interface{} {
+	return s.extraProperties
+}
+
+func (s *SpfMechanism) UnmarshalJSON(data []byte) error {
+	type unmarshaler SpfMechanism
+	var value unmarshaler
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*s = SpfMechanism(value)
+
+	extraProperties, err := core.ExtractExtraProperties(data, *s)
+	if err != nil {
+		return err
+	}
+	s.extraProperties = extraProperties
+
+	s._rawJSON = json.RawMessage(data)
+	return nil
+}
+
+func (s *SpfMechanism) String() string {
+	if len(s._rawJSON) > 0 {
+		if value, err := core.StringifyJSON(s._rawJSON); err == nil {
+			return value
+		}
+	}
+	if value, err := core.StringifyJSON(s); err == nil {
+		return value
+	}
+	return fmt.Sprintf("%#v", s)
+}
+
+type SpfPolicy struct {
+	Domain                  string        `json:"domain" url:"domain"`
+	RecordCount             int           `json:"recordCount" url:"recordCount"`
+	DuplicateRecords        bool          `json:"duplicateRecords" url:"duplicateRecords"`
+	Root                    *SpfRecord    `json:"root,omitempty" url:"root,omitempty"`
+	LookupCount             int           `json:"lookupCount" url:"lookupCount"`
+	LookupLimitExceeded     bool          `json:"lookupLimitExceeded" url:"lookupLimitExceeded"`
+	VoidLookupCount         int           `json:"voidLookupCount" url:"voidLookupCount"`
+	VoidLookupLimitExceeded bool          `json:"voidLookupLimitExceeded" url:"voidLookupLimitExceeded"`
+	AllQualifier            *SpfQualifier `json:"allQualifier,omitempty" url:"allQualifier,omitempty"`
+	PermissiveAll           bool          `json:"permissiveAll" url:"permissiveAll"`
+	Ip4                     []string      `json:"ip4,omitempty" url:"ip4,omitempty"`
+	Ip6                     []string      `json:"ip6,omitempty" url:"ip6,omitempty"`
+	Findings                []string      `json:"findings,omitempty" url:"findings,omitempty"`
+
+	extraProperties map[string]interface{}
+	_rawJSON        json.RawMessage
+}
+
+func (s *SpfPolicy) GetExtraProperties() map[string]interface{} {
+	return s.extraProperties
+}
+
+func (s *SpfPolicy) UnmarshalJSON(data []byte) error {
+	type unmarshaler SpfPolicy
+	var value unmarshaler
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*s = SpfPolicy(value)
+
+	extraProperties, err := core.ExtractExtraProperties(data, *s)
+	if err != nil {
+		return err
+	}
+	s.extraProperties = extraProperties
+
+	s._rawJSON = json.RawMessage(data)
+	return nil
+}
+
+func (s *SpfPolicy) String() string {
+	if len(s._rawJSON) > 0 {
+		if value, err := core.StringifyJSON(s._rawJSON); err == nil {
+			return value
+		}
+	}
+	if value, err := core.StringifyJSON(s); err == nil {
+		return value
+	}
+	return fmt.Sprintf("%#v", s)
+}
+
+type SpfQualifier string
+
+const (
+	SpfQualifierPass     SpfQualifier = "PASS"
+	SpfQualifierFail     SpfQualifier = "FAIL"
+	SpfQualifierSoftfail SpfQualifier = "SOFTFAIL"
+	SpfQualifierNeutral  SpfQualifier = "NEUTRAL"
+)
+
+func NewSpfQualifierFromString(s string) (SpfQualifier, error) {
+	switch s {
+	case "PASS":
+		return SpfQualifierPass, nil
+	case "FAIL":
+		return SpfQualifierFail, nil
+	case "SOFTFAIL":
+		return SpfQualifierSoftfail, nil
+	case "NEUTRAL":
+		return SpfQualifierNeutral, nil
+	}
+	var t SpfQualifier
+	return "", fmt.Errorf("%s is not a valid %T", s, t)
+}
+
+func (s SpfQualifier) Ptr() *SpfQualifier {
+	return &s
+}
+
+type SpfRecord struct {
+	Domain     string          `json:"domain" url:"domain"`
+	Record     *string         `json:"record,omitempty" url:"record,omitempty"`
+	Mechanisms []*SpfMechanism `json:"mechanisms,omitempty" url:"mechanisms,omitempty"`
+	Redirect   *SpfMechanism   `json:"redirect,omitempty" url:"redirect,omitempty"`
+	Errors     []string        `json:"errors,omitempty" url:"errors,omitempty"`
+
+	extraProperties map[string]interface{}
+	_rawJSON        json.RawMessage
+}
+
+func (s *SpfRecord) GetExtraProperties() map[string]interface{} {
+	return s.extraProperties
+}
+
+func (s *SpfRecord) UnmarshalJSON(data []byte) error {
+	type unmarshaler SpfRecord
+	var value unmarshaler
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*s = SpfRecord(value)
+
+	extraProperties, err := core.ExtractExtraProperties(data, *s)
+	if err != nil {
+		return err
+	}
+	s.extraProperties = extraProperties
+
+	s._rawJSON = json.RawMessage(data)
+	return nil
+}
+
+func (s *SpfRecord) String() string {
+	if len(s._rawJSON) > 0 {
+		if value, err := core.StringifyJSON(s._rawJSON); err == nil {
+			return value
+		}
+	}
+	if value, err := core.StringifyJSON(s); err == nil {
+		return value
+	}
+	return fmt.Sprintf("%#v", s)
+}
+
+type SpoofabilityAssessment struct {
+	Verdict SpoofabilityVerdict `json:"verdict" url:"verdict"`
+	Reasons []string            `json:"reasons,omitempty" url:"reasons,omitempty"`
+
+	extraProperties map[string]interface{}
+	_rawJSON        json.RawMessage
+}
+
+func (s *SpoofabilityAssessment) GetExtraProperties() map[string]interface{} {
+	return s.extraProperties
+}
+
+func (s *SpoofabilityAssessment) UnmarshalJSON(data []byte) error {
+	type unmarshaler SpoofabilityAssessment
+	var value unmarshaler
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*s = SpoofabilityAssessment(value)
+
+	extraProperties, err := core.ExtractExtraProperties(data, *s)
+	if err != nil {
+		return err
+	}
+	s.extraProperties = extraProperties
+
+	s._rawJSON = json.RawMessage(data)
+	return nil
+}
+
+func (s *SpoofabilityAssessment) String() string {
+	if len(s._rawJSON) > 0 {
+		if value, err := core.StringifyJSON(s._rawJSON); err == nil {
+			return value
+		}
+	}
+	if value, err := core.StringifyJSON(s); err == nil {
+		return value
+	}
+	return fmt.Sprintf("%#v", s)
+}
+
+type SpoofabilityVerdict string
+
+const (
+	SpoofabilityVerdictSpoofable         SpoofabilityVerdict = "SPOOFABLE"
+	SpoofabilityVerdictPossiblySpoofable SpoofabilityVerdict = "POSSIBLY_SPOOFABLE"
+	SpoofabilityVerdictNotSpoofable      SpoofabilityVerdict = "NOT_SPOOFABLE"
+)
+
+func NewSpoofabilityVerdictFromString(s string) (SpoofabilityVerdict, error) {
+	switch s {
+	case "SPOOFABLE":
+		return SpoofabilityVerdictSpoofable, nil
+	case "POSSIBLY_SPOOFABLE":
+		return SpoofabilityVerdictPossiblySpoofable, nil
+	case "NOT_SPOOFABLE":
+		return SpoofabilityVerdictNotSpoofable, nil
+	}
+	var t SpoofabilityVerdict
+	return "", fmt.Errorf("%s is not a valid %T", s, t)
+}
+
+func (s SpoofabilityVerdict) Ptr() *SpoofabilityVerdict {
+	return &s
+}
+
+type Subdomain struct {
+	Name       string            `json:"name" url:"name"`
+	Parent     string            `json:"parent" url:"parent"`
+	Depth      int               `json:"depth" url:"depth"`
+	Token      *string           `json:"token,omitempty" url:"token,omitempty"`
+	Addresses  []string          `json:"addresses,omitempty" url:"addresses,omitempty"`
+	CnameChain []string          `json:"cnameChain,omitempty" url:"cnameChain,omitempty"`
+	FirstSeen  time.Time         `json:"firstSeen" url:"firstSeen"`
+	Method     SubdomainMethod   `json:"method" url:"method"`
+	Methods    []SubdomainMethod `json:"methods,omitempty" url:"methods,omitempty"`
+
+	extraProperties map[string]interface{}
+	_rawJSON        json.RawMessage
+}
+
+func (s *Subdomain) GetExtraProperties() map[string]interface{} {
+	return s.extraProperties
+}
+
+func (s *Subdomain) UnmarshalJSON(data []byte) error {
+	type embed Subdomain
+	var unmarshaler = struct {
+		embed
+		FirstSeen *core.DateTime `json:"firstSeen"`
+	}{
+		embed: embed(*s),
+	}
+	if err := json.Unmarshal(data, &unmarshaler); err != nil {
+		return err
+	}
+	*s = Subdomain(unmarshaler.embed)
+	s.FirstSeen = unmarshaler.FirstSeen.Time()
+
+	extraProperties, err := core.ExtractExtraProperties(data, *s)
+	if err != nil {
+		return err
+	}
+	s.extraProperties = extraProperties
+
+	s._rawJSON = json.RawMessage(data)
+	return nil
+}
+
+func (s *Subdomain) MarshalJSON() ([]byte, error) {
+	type embed Subdomain
+	var marshaler = struct {
+		embed
+		FirstSeen *core.DateTime `json:"firstSeen"`
+	}{
+		embed:     embed(*s),
+		FirstSeen: core.NewDateTime(s.FirstSeen),
+	}
+	return json.Marshal(marshaler)
+}
+
+func (s *Subdomain) String() string {
+	if len(s._rawJSON) > 0 {
+		if value, err := core.StringifyJSON(s._rawJSON); err == nil {
+			return value
+		}
+	}
+	if value, err := core.StringifyJSON(s); err == nil {
+		return value
+	}
+	return fmt.Sprintf("%#v", s)
+}
+
+type SubdomainMethod string
+
+const (
+	SubdomainMethodBrute       SubdomainMethod = "BRUTE"
+	SubdomainMethodPassive     SubdomainMethod = "PASSIVE"
+	SubdomainMethodWalk        SubdomainMethod = "WALK"
+	SubdomainMethodPermute     SubdomainMethod = "PERMUTE"
+	SubdomainMethodCertificate SubdomainMethod = "CERTIFICATE"
+)
+
+func NewSubdomainMethodFromString(s string) (SubdomainMethod, error) {
+	switch s {
+	case "BRUTE":
+		return SubdomainMethodBrute, nil
+	case "PASSIVE":
+		return SubdomainMethodPassive, nil
+	case "WALK":
+		return SubdomainMethodWalk, nil
+	case "PERMUTE":
+		return SubdomainMethodPermute, nil
+	case "CERTIFICATE":
+		return SubdomainMethodCertificate, nil
+	}
+	var t SubdomainMethod
+	return "", fmt.Errorf("%s is not a valid %T", s, t)
+}
+
+func (s SubdomainMethod) Ptr() *SubdomainMethod {
+	return &s
+}
+
+type TlsRptPolicy struct {
+	Status   EmailControlStatus `json:"status" url:"status"`
+	Record   *string            `json:"record,omitempty" url:"record,omitempty"`
+	Rua      []string           `json:"rua,omitempty" url:"rua,omitempty"`
+	Findings []string           `json:"findings,omitempty" url:"findings,omitempty"`
+
+	extraProperties map[string]interface{}
+	_rawJSON        json.RawMessage
+}
+
+func (t *TlsRptPolicy) GetExtraProperties() map[string]interface{} {
+	return t.extraProperties
+}
+
+func (t *TlsRptPolicy) UnmarshalJSON(data []byte) error {
+	type unmarshaler TlsRptPolicy
+	var value unmarshaler
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*t = TlsRptPolicy(value)
+
+	extraProperties, err := core.ExtractExtraProperties(data, *t)
+	if err != nil {
+		return err
+	}
+	t.extraProperties = extraProperties
+
+	t._rawJSON = json.RawMessage(data)
+	return nil
+}
+
+func (t *TlsRptPolicy) String() string {
+	if len(t._rawJSON) > 0 {
+		if value, err := core.StringifyJSON(t._rawJSON); err == nil {
+			return value
+		}
+	}
+	if value, err := core.StringifyJSON(t); err == nil {
+		return value
+	}
+	return fmt.Sprintf("%#v", t)
+}
+
+type ZoneTransferAttempt struct {
+	Nameserver  string              `json:"nameserver" url:"nameserver"`
+	Address     string              `json:"address" url:"address"`
+	Type        ZoneTransferType    `json:"type" url:"type"`
+	Outcome     ZoneTransferOutcome `json:"outcome" url:"outcome"`
+	Rcode       *string             `json:"rcode,omitempty" url:"rcode,omitempty"`
+	RecordCount int                 `json:"recordCount" url:"recordCount"`
+	DurationMs  int                 `json:"durationMs" url:"durationMs"`
+	Error       *string             `json:"error,omitempty" url:"error,omitempty"`
+
+	extraProperties map[string]interface{}
+	_rawJSON        json.RawMessage
+}
+
+func (z *ZoneTransferAttempt) GetExtraProperties() map[string]interface{} {
+	return z.extraProperties
+}
+
+func (z *ZoneTransferAttempt) UnmarshalJSON(data []byte) error {
+	type unmarshaler ZoneTransferAttempt
+	var value unmarshaler
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*z = ZoneTransferAttempt(value)
+
+	extraProperties, err := core.ExtractExtraProperties(data, *z)
+	if err != nil {
+		return err
+	}
+	z.extraProperties = extraProperties
+
+	z._rawJSON = json.RawMessage(data)
+	return nil
+}
+
+func (z *ZoneTransferAttempt) String() string {
+	if len(z._rawJSON) > 0 {
+		if value, err := core.StringifyJSON(z._rawJSON); err == nil {
+			return value
+		}
+	}
+	if value, err := core.StringifyJSON(z); err == nil {
+		return value
+	}
+	return fmt.Sprintf("%#v", z)
+}
+
+type ZoneTransferOutcome string
+
+const (
+	ZoneTransferOutcomeSuccess ZoneTransferOutcome = "SUCCESS"
+	ZoneTransferOutcomeRefused ZoneTransferOutcome = "REFUSED"
+	ZoneTransferOutcomeTimeout ZoneTransferOutcome = "TIMEOUT"
+	ZoneTransferOutcomeError   ZoneTransferOutcome = "ERROR"
+)
+
+func NewZoneTransferOutcomeFromString(s string) (ZoneTransferOutcome, error) {
+	switch s {
+	case "SUCCESS":
+		return ZoneTransferOutcomeSuccess, nil
+	case "REFUSED":
+		return ZoneTransferOutcomeRefused, nil
+	case "TIMEOUT":
+		return ZoneTransferOutcomeTimeout, nil
+	case "ERROR":
+		return ZoneTransferOutcomeError, nil
+	}
+	var t ZoneTransferOutcome
+	return "", fmt.Errorf("%s is not a valid %T", s, t)
+}
+
+func (z ZoneTransferOutcome) Ptr() *ZoneTransferOutcome {
+	return &z
+}
+
+type ZoneTransferReport struct {
+	Domain      string                 `json:"domain" url:"domain"`
+	Nameservers []string               `json:"nameservers,omitempty" url:"nameservers,omitempty"`
+	Vulnerable  bool                   `json:"vulnerable" url:"vulnerable"`
+	Attempts    []*ZoneTransferAttempt `json:"attempts,omitempty" url:"attempts,omitempty"`
+	Records     []*DnsRecord           `json:"records,omitempty" url:"records,omitempty"`
+	Errors      []string               `json:"errors,omitempty" url:"errors,omitempty"`
+
+	extraProperties map[string]interface{}
+	_rawJSON        json.RawMessage
+}
+
+func (z *ZoneTransferReport) GetExtraProperties() map[string]interface{} {
+	return z.extraProperties
+}
+
+func (z *ZoneTransferReport) UnmarshalJSON(data []byte) error {
+	type unmarshaler ZoneTransferReport
+	var value unmarshaler
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*z = ZoneTransferReport(value)
+
+	extraProperties, err := core.ExtractExtraProperties(data, *z)
+	if err != nil {
+		return err
+	}
+	z.extraProperties = extraProperties
+
+	z._rawJSON = json.RawMessage(data)
+	return nil
+}
+
+func (z *ZoneTransferReport) String() string {
+	if len(z._rawJSON) > 0 {
+		if value, err := core.StringifyJSON(z._rawJSON); err == nil {
+			return value
+		}
+	}
+	if value, err := core.StringifyJSON(z); err == nil {
+		return value
+	}
+	return fmt.Sprintf("%#v", z)
+}
+
+type ZoneTransferType string
+
+const (
+	ZoneTransferTypeAxfr ZoneTransferType = "AXFR"
+	ZoneTransferTypeIxfr ZoneTransferType = "IXFR"
+)
+
+func NewZoneTransferTypeFromString(s string) (ZoneTransferType, error) {
+	switch s {
+	case "AXFR":
+		return ZoneTransferTypeAxfr, nil
+	case "IXFR":
+		return ZoneTransferTypeIxfr, nil
+	}
+	var t ZoneTransferType
+	return "", fmt.Errorf("%s is not a valid %T", s, t)
+}
+
+func (z ZoneTransferType) Ptr() *ZoneTransferType {
+	return &z
 }
@@ -0,0 +1,130 @@
+package dns
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	osintscan "github.com/Method-Security/osintscan/generated/go"
+	"github.com/miekg/dns"
+)
+
+// GetDomainSubdomainsAll enumerates subdomains of the domain with every technique and merges the results. Passive
+// sources and the certificates logged by crt.sh are queried at the same time, and the names they return are resolved
+// and marked live, dead or wildcard. Brute force then runs with the wordlist, recursing into the names that exist as
+// well as the ones it finds, up to recursiveDepth. Finally, up to maxCandidates variants of every name that exists,
+// whichever technique found it, are generated with the tokens and resolved, so names found by brute force seed the
+// permutations as well. Variants that exist are not permuted or brute forced in turn. Every name is reported once, with
+// all of the methods that found it. The passive config also sets the number of queries in flight and the rate limit of
+// every resolution. It returns a DnsSubenumReport struct containing all subdomains and any errors that occurred.
+func GetDomainSubdomainsAll(ctx context.Context, domain string, config PassiveConfig, subdomainList []string, recursiveDepth int, tokens []string, maxCandidates int, timeout int, resolver *Resolver) (osintscan.DnsSubenumReport, error) {
+	report := osintscan.DnsSubenumReport{
+		Domain:          domain,
+		EnumerationType: osintscan.DnsSubenumTypeAll,
+		Subdomains:      []string{},
+	}
+	errors := []string{}
+
+	if err := validatePassiveConfig(config); err != nil {
+		return report, err
+	}
+	engine, err := NewMassResolver(resolver, MassResolverConfig{InFlight: config.Threads, RateLimit: config.RateLimit})
+	if err != nil {
+		return report, err
+	}
+	fingerprints, err := retrieveFingerprints("")
+	if err != nil {
+		errors = append(errors, fmt.Sprintf("dangling CNAMEs will not be matched to services: %s", err.Error()))
+	}
+
+	var cancel context.CancelFunc
+	if timeout != 0 {
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(timeout)*time.Minute)
+		defer cancel()
+	}
+
+	// Passive sources and certificate transparency are independent, so they are queried at the same time
+	var sources map[string][]string
+	var passiveErr error
+	var certificates []string
+	var certificateErrors []string
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		sources, passiveErr = getSubdomainsPassive(ctx, domain, config)
+	}()
+	go func() {
+		defer wg.Done()
+		certificates, certificateErrors = getSubdomainsCertificates(ctx, domain)
+	}()
+	wg.Wait()
+	if passiveErr != nil {
+		errors = append(errors, passiveErr.Error())
+	}
+	sources, dropped := scopeSources(domain, sources)
+	if dropped > 0 {
+		errors = append(errors, fmt.Sprintf("dropped %d names that are not subdomains of %s", dropped, normalizeName(domain)))
+	}
+	errors = append(errors, certificateErrors...)
+
+	methods := map[string][]osintscan.SubdomainMethod{}
+	for name := range sources {
+		methods[name] = append(methods[name], osintscan.SubdomainMethodPassive)
+	}
+	for _, name := range certificates {
+		methods[name] = append(methods[name], osintscan.SubdomainMethodCertificate)
+	}
+	for name := range methods {
+		report.Subdomains = append(report.Subdomains, name)
+	}
+	sort.Strings(report.Subdomains)
+	for _, name := range report.Subdomains {
+		subdomain := newSubdomain(domain, name, methods[name][0])
+		subdomain.Methods = methods[name]
+		report.SubdomainDetails = append(report.SubdomainDetails, subdomain)
+	}
+	report.Sources = sources
+	errors = append(errors, resolveSubdomains(ctx, &report, config, resolver)...)
+
+	// Every passive or certificate name that exists seeds the brute force recursion
+	checkpoint := newBruteCheckpoint(domain, subdomainList, recursiveDepth)
+	checkpoint.Report = &report
+	for _, resolution := range report.Resolutions {
+		if resolution.Status != osintscan.DnsResolutionStatusWildcard && resolution.Rcode == dns.RcodeToString[dns.RcodeSuccess] {
+			checkpoint.Seeds = append(checkpoint.Seeds, resolution.Name)
+		}
+	}
+	passiveNames := len(report.Subdomains)
+	if ctx.Err() == nil {
+		errors = append(errors, getSubdomainsBrute(ctx, subdomainList, 0, engine, fingerprints, checkpoint, "")...)
+	}
+
+	// Every name that exists is permuted, including the ones brute force found
+	names := slices.Concat(checkpoint.Seeds, report.Subdomains[passiveNames:], report.NoAddressNames)
+	if ctx.Err() == nil && maxCandidates > 0 && len(names) > 0 {
+		reported := reportedNames(&report)
+		errors = append(errors, permuteSubdomains(ctx, &report, reported, names, normalizeName(domain), tokens, maxCandidates, engine, fingerprints)...)
+	}
+
+	// Brute force and permutation detect the wildcards of parents the resolution of passive names already did
+	wildcards := map[string]bool{}
+	report.Wildcards = slices.DeleteFunc(report.Wildcards, func(wildcard *osintscan.DnsWildcard) bool {
+		duplicate := wildcards[wildcard.Domain]
+		wildcards[wildcard.Domain] = true
+		return duplicate
+	})
+	dangling := map[string]bool{}
+	report.DanglingCnames = slices.DeleteFunc(report.DanglingCnames, func(record *osintscan.DnsDanglingCname) bool {
+		duplicate := dangling[record.Name]
+		dangling[record.Name] = true
+		return duplicate
+	})
+	errors = append(errors, separateEmptyNonTerminals(ctx, &report, resolver)...)
+
+	report.Errors = errors
+	return report, nil
+}
@@ -0,0 +1,178 @@
+package dns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	osintscan "github.com/Method-Security/osintscan/generated/go"
+	"github.com/miekg/dns"
+)
+
+// CheckDomainZoneTransfer attempts AXFR and IXFR zone transfers of the domain against every IPv4 and IPv6 address of
+// every authoritative nameserver, with at most parallelThreads attempts running at once. It returns a
+// ZoneTransferReport struct with the outcome of every attempt and the records of any transfer that succeeded, along
+// with any non-fatal errors that occurred.
+func CheckDomainZoneTransfer(ctx context.Context, domain string, resolver *Resolver, timeout int, parallelThreads int) (osintscan.ZoneTransferReport, error) {
+	errors := []string{}
+	report := osintscan.ZoneTransferReport{Domain: domain}
+
+	nsRecords, err := queryRecords(ctx, resolver, domain, dns.TypeNS)
+	if err != nil {
+		return report, err
+	}
+	if len(nsRecords) == 0 {
+		return report, fmt.Errorf("no NS records found for %s", domain)
+	}
+
+	// IXFR requests carry the serial the client already has; serial 0 asks for the full history of the zone
+	ixfrMsg := &dns.Msg{}
+	ixfrMsg.SetIxfr(dns.Fqdn(domain), 0, ".", ".")
+	soaRecords, err := queryRecords(ctx, resolver, domain, dns.TypeSOA)
+	if err != nil {
+		errors = append(errors, err.Error())
+	} else if len(soaRecords) > 0 && soaRecords[0].Soa != nil {
+		ixfrMsg.SetIxfr(dns.Fqdn(domain), 0, dns.Fqdn(soaRecords[0].Soa.Mname), dns.Fqdn(soaRecords[0].Soa.Rname))
+	}
+	axfrMsg := &dns.Msg{}
+	axfrMsg.SetAxfr(dns.Fqdn(domain))
+
+	var attempts []*osintscan.ZoneTransferAttempt
+	for _, nsRecord := range nsRecords {
+		nameserver := nsRecord.Value
+		report.Nameservers = append(report.Nameservers, nameserver)
+		addresses, err := resolver.LookupHost(ctx, nameserver)
+		if err != nil {
+			errors = append(errors, fmt.Sprintf("could not resolve nameserver %s: %s", nameserver, err.Error()))
+			continue
+		}
+		for _, address := range addresses {
+			for _, transferType := range []osintscan.ZoneTransferType{osintscan.ZoneTransferTypeAxfr, osintscan.ZoneTransferTypeIxfr} {
+				attempts = append(attempts, &osintscan.ZoneTransferAttempt{
+					Nameserver: nameserver,
+					Address:    address,
+					Type:       transferType,
+				})
+			}
+		}
+	}
+
+	// Attempts are independent and mostly spend their time waiting on the network, so a pool of workers runs them
+	if parallelThreads <= 0 {
+		parallelThreads = 1
+	}
+	results := make([][]dns.RR, len(attempts))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for worker := 0; worker < min(parallelThreads, len(attempts)); worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				msg := axfrMsg
+				if attempts[i].Type == osintscan.ZoneTransferTypeIxfr {
+					msg = ixfrMsg
+				}
+				results[i] = transferZone(ctx, msg.Copy(), attempts[i], time.Duration(timeout)*time.Second)
+			}
+		}()
+	}
+	for i := range attempts {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	seen := map[string]bool{}
+	for i, attempt := range attempts {
+		report.Attempts = append(report.Attempts, attempt)
+		if attempt.Outcome != osintscan.ZoneTransferOutcomeSuccess {
+			continue
+		}
+		report.Vulnerable = true
+		for _, rr := range results[i] {
+			if seen[rr.String()] {
+				continue
+			}
+			seen[rr.String()] = true
+			report.Records = append(report.Records, dnsRecordFromRR(rr))
+		}
+	}
+
+	report.Errors = errors
+	return report, nil
+}
+
+// transferZone performs a single zone transfer against the address and records its outcome on the attempt. It returns
+// the transferred records when the transfer succeeded.
+func transferZone(ctx context.Context, msg *dns.Msg, attempt *osintscan.ZoneTransferAttempt, timeout time.Duration) []dns.RR {
+	attempt.Outcome = osintscan.ZoneTransferOutcomeError
+	start := time.Now()
+	defer func() {
+		attempt.DurationMs = int(time.Since(start).Milliseconds())
+	}()
+
+	dialer := &net.Dialer{Timeout: timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(attempt.Address, "53"))
+	if err != nil {
+		setTransferError(attempt, err)
+		return nil
+	}
+	transfer := &dns.Transfer{
+		Conn:        &dns.Conn{Conn: conn},
+		DialTimeout: timeout,
+		ReadTimeout: timeout,
+	}
+	envelopes, err := transfer.In(msg, conn.RemoteAddr().String())
+	if err != nil {
+		_ = conn.Close()
+		setTransferError(attempt, err)
+		return nil
+	}
+
+	var rrs []dns.RR
+	for envelope := range envelopes {
+		if envelope.Error != nil {
+			setTransferError(attempt, envelope.Error)
+			// Drain the channel so the transfer goroutine can exit
+			for range envelopes {
+			}
+			return nil
+		}
+		rrs = append(rrs, envelope.RR...)
+	}
+
+	rcode := dns.RcodeToString[dns.RcodeSuccess]
+	attempt.Rcode = &rcode
+	attempt.RecordCount = len(rrs)
+	attempt.Outcome = osintscan.ZoneTransferOutcomeSuccess
+	return rrs
+}
+
+// setTransferError classifies a failed transfer as refused, timed out or failed.
+func setTransferError(attempt *osintscan.ZoneTransferAttempt, err error) {
+	message := err.Error()
+	attempt.Error = &message
+
+	var rcode int
+	var netErr net.Error
+	switch {
+	case errors.As(err, &netErr) && netErr.Timeout():
+		attempt.Outcome = osintscan.ZoneTransferOutcomeTimeout
+	case errors.Is(err, dns.ErrSoa):
+		// Some servers refuse transfers with an empty NOERROR response, which the transfer reports as a missing SOA
+		attempt.Outcome = osintscan.ZoneTransferOutcomeRefused
+	case strings.HasPrefix(message, "dns: bad xfr rcode: "):
+		if _, scanErr := fmt.Sscanf(message, "dns: bad xfr rcode: %d", &rcode); scanErr == nil {
+			rcodeName := dns.RcodeToString[rcode]
+			attempt.Rcode = &rcodeName
+		}
+		attempt.Outcome = osintscan.ZoneTransferOutcomeRefused
+	default:
+		attempt.Outcome = osintscan.ZoneTransferOutcomeError
+	}
+}
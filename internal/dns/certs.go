@@ -8,6 +8,8 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"sort"
+	"strings"
 )
 
 // CertificateRecord represents all of the information for a single x509 certificate record.
@@ -41,9 +43,13 @@ func GetDomainCerts(ctx context.Context, domain string) (CertsReport, error) {
 	apiURL := fmt.Sprintf(baseURL, escapedDomain)
 
 	// 1. Make the HTTP request to crt.sh API
-	resp, err := http.Get(apiURL)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
 	if err != nil {
-		errors = append(errors, err.Error())
+		return CertsReport{Domain: domain, Errors: append(errors, err.Error())}, nil
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return CertsReport{Domain: domain, Errors: append(errors, err.Error())}, nil
 	}
 	defer func() {
 		// Capture and log any error from Close
@@ -74,3 +80,36 @@ func GetDomainCerts(ctx context.Context, domain string) (CertsReport, error) {
 
 	return report, nil
 }
+
+// getSubdomainsCertificates returns the subdomains of the domain named by the certificates crt.sh has logged for it and
+// its subdomains, sorted. Wildcard names are reported as the domain they cover.
+func getSubdomainsCertificates(ctx context.Context, domain string) ([]string, []string) {
+	report, _ := GetDomainCerts(ctx, "%."+domain)
+	zone := normalizeName(domain)
+
+	seen := map[string]bool{}
+	var subdomains []string
+	for _, name := range certificateNames(report.Certificates) {
+		if seen[name] || !isSubdomain(name, zone) {
+			continue
+		}
+		seen[name] = true
+		subdomains = append(subdomains, name)
+	}
+	sort.Strings(subdomains)
+	return subdomains, report.Errors
+}
+
+// certificateNames returns the common name and subject alternative names of every certificate, normalized. Wildcard
+// names are returned as the domain they cover.
+func certificateNames(records []CertificateRecord) []string {
+	var names []string
+	for _, record := range records {
+		for _, name := range append(strings.Split(record.NameValue, "\n"), record.CommonName) {
+			if name = normalizeName(strings.TrimPrefix(strings.TrimSpace(name), "*.")); name != "" {
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
@@ -0,0 +1,111 @@
+package dns
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	osintscan "github.com/Method-Security/osintscan/generated/go"
+)
+
+// BruteCheckpoint is the progress of a brute force enumeration. It is written periodically during a run so that a run
+// that crashed, was interrupted or hit its timeout can be resumed where it stopped.
+type BruteCheckpoint struct {
+	Domain       string `json:"domain"`
+	WordlistHash string `json:"wordlistHash"`
+	MaxDepth     int    `json:"maxDepth"`
+	Complete     bool   `json:"complete"`
+
+	// Depth is the recursion depth being tested and Parents the subdomains the wordlist is tested under at that depth
+	Depth   int      `json:"depth"`
+	Parents []string `json:"parents"`
+	// Index is the number of permutations of the depth that have been resolved; every permutation before it is done
+	Index int `json:"index"`
+	// WildcardsDetected reports whether the wildcards of the depth's parents have been detected into DepthWildcards
+	WildcardsDetected bool                     `json:"wildcardsDetected"`
+	DepthWildcards    []*osintscan.DnsWildcard `json:"depthWildcards"`
+	// DepthSubdomains are the names found at the depth so far, including the ones without addresses, which become the parents
+	// of the next depth
+	DepthSubdomains []string `json:"depthSubdomains"`
+	// Seeds are names known before the run, which are recursed into at their depth like the names found there
+	Seeds []string `json:"seeds,omitempty"`
+
+	// Report holds everything found so far
+	Report *osintscan.DnsSubenumReport `json:"report"`
+}
+
+// newBruteCheckpoint returns the checkpoint of a run that has not started yet.
+func newBruteCheckpoint(domain string, subdomainList []string, recursiveDepth int) *BruteCheckpoint {
+	return &BruteCheckpoint{
+		Domain:       domain,
+		WordlistHash: wordlistHash(subdomainList),
+		MaxDepth:     recursiveDepth,
+		Depth:        1,
+		Parents:      []string{domain},
+		Report: &osintscan.DnsSubenumReport{
+			Domain:          domain,
+			EnumerationType: osintscan.DnsSubenumTypeBrute,
+			Subdomains:      []string{},
+		},
+	}
+}
+
+// LoadBruteCheckpoint reads a checkpoint written by a previous brute force run.
+func LoadBruteCheckpoint(path string) (*BruteCheckpoint, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	checkpoint := &BruteCheckpoint{}
+	if err := json.Unmarshal(data, checkpoint); err != nil || checkpoint.Report == nil {
+		return nil, fmt.Errorf("invalid checkpoint %s", path)
+	}
+	return checkpoint, nil
+}
+
+// matches returns an error when the checkpoint was written by a run with a different domain, wordlist or depth, since
+// its progress would not carry over.
+func (c *BruteCheckpoint) matches(domain string, subdomainList []string, recursiveDepth int) error {
+	switch {
+	case c.Domain != domain:
+		return fmt.Errorf("checkpoint is for %s, not %s", c.Domain, domain)
+	case c.WordlistHash != wordlistHash(subdomainList):
+		return fmt.Errorf("checkpoint was written with a different wordlist")
+	case c.MaxDepth != recursiveDepth:
+		return fmt.Errorf("checkpoint was written with a maximum depth of %d, not %d", c.MaxDepth, recursiveDepth)
+	}
+	return nil
+}
+
+// write saves the checkpoint to a temporary file next to the path and renames it over the path, so that a crash while
+// writing never leaves a truncated checkpoint behind.
+func (c *BruteCheckpoint) write(path string) error {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	file, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(data); err != nil {
+		_ = file.Close()
+		_ = os.Remove(file.Name())
+		return err
+	}
+	if err := file.Close(); err != nil {
+		_ = os.Remove(file.Name())
+		return err
+	}
+	return os.Rename(file.Name(), path)
+}
+
+// wordlistHash identifies the wordlist, including the order of its words, which the permutation index depends on.
+func wordlistHash(subdomainList []string) string {
+	hash := sha256.Sum256([]byte(strings.Join(subdomainList, "\n")))
+	return hex.EncodeToString(hash[:])
+}
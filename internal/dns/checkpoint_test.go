@@ -0,0 +1,139 @@
+package dns
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestBruteCheckpointMatches(t *testing.T) {
+	wordlist := []string{"www", "api", "mail"}
+	checkpoint := newBruteCheckpoint("example.com", wordlist, 2)
+
+	tests := []struct {
+		name     string
+		domain   string
+		wordlist []string
+		depth    int
+		wantErr  bool
+	}{
+		{name: "same run", domain: "example.com", wordlist: []string{"www", "api", "mail"}, depth: 2},
+		{name: "another domain", domain: "example.org", wordlist: wordlist, depth: 2, wantErr: true},
+		{name: "another wordlist", domain: "example.com", wordlist: []string{"www", "api", "vpn"}, depth: 2, wantErr: true},
+		{name: "reordered wordlist", domain: "example.com", wordlist: []string{"api", "www", "mail"}, depth: 2, wantErr: true},
+		{name: "longer wordlist", domain: "example.com", wordlist: []string{"www", "api", "mail", "vpn"}, depth: 2, wantErr: true},
+		{name: "another depth", domain: "example.com", wordlist: wordlist, depth: 3, wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := checkpoint.matches(test.domain, test.wordlist, test.depth)
+			if (err != nil) != test.wantErr {
+				t.Errorf("got error %v, want error %t", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestBruteCheckpointWrite(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "checkpoint.json")
+	checkpoint := newBruteCheckpoint("example.com", []string{"www", "api"}, 2)
+	checkpoint.Index = 1
+	checkpoint.DepthSubdomains = []string{"www.example.com"}
+	checkpoint.Report.Subdomains = append(checkpoint.Report.Subdomains, "www.example.com")
+
+	// Writing twice replaces the checkpoint without leaving temporary files behind
+	for i := 0; i < 2; i++ {
+		if err := checkpoint.write(path); err != nil {
+			t.Fatal(err)
+		}
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("got %d files in the checkpoint directory, want only the checkpoint", len(entries))
+	}
+
+	loaded, err := LoadBruteCheckpoint(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Index != 1 || !slices.Equal(loaded.DepthSubdomains, checkpoint.DepthSubdomains) || !slices.Equal(loaded.Report.Subdomains, checkpoint.Report.Subdomains) {
+		t.Errorf("got checkpoint %+v, want %+v", loaded, checkpoint)
+	}
+	if err := loaded.matches("example.com", []string{"www", "api"}, 2); err != nil {
+		t.Errorf("loaded checkpoint does not match the run that wrote it: %s", err)
+	}
+
+	for name, content := range map[string]string{"truncated": `{"domain": "example.com"`, "without report": `{"domain": "example.com"}`} {
+		invalid := filepath.Join(dir, name+".json")
+		if err := os.WriteFile(invalid, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadBruteCheckpoint(invalid); err == nil {
+			t.Errorf("loaded the %s checkpoint, want an error", name)
+		}
+	}
+}
+
+func TestGetDomainSubdomainsBruteResume(t *testing.T) {
+	resolver := startZoneServer(t,
+		`www.example.com. 300 IN A 192.0.2.1`,
+		`api.example.com. 300 IN A 192.0.2.2`,
+		`mail.example.com. 300 IN A 192.0.2.3`,
+		`dev.api.example.com. 300 IN A 192.0.2.4`,
+	)
+	wordlist := []string{"www", "api", "mail", "dev"}
+	path := filepath.Join(t.TempDir(), "checkpoint.json")
+
+	t.Run("resumes at the checkpoint's index", func(t *testing.T) {
+		// The first permutation was resolved before the run stopped, so www is only known from the checkpoint
+		checkpoint := newBruteCheckpoint("example.com", wordlist, 2)
+		checkpoint.Index = 1
+		report, err := GetDomainSubdomainsBrute(context.Background(), "example.com", wordlist, 4, 0, 2, 0, "", path, checkpoint, resolver)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := []string{"api.example.com", "dev.api.example.com", "mail.example.com"}; !slices.Equal(sorted(report.Subdomains), want) {
+			t.Errorf("got subdomains %v, want %v", report.Subdomains, want)
+		}
+	})
+
+	t.Run("a complete checkpoint returns its report", func(t *testing.T) {
+		checkpoint, err := LoadBruteCheckpoint(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !checkpoint.Complete {
+			t.Fatal("got an incomplete checkpoint after the run completed")
+		}
+		report, err := GetDomainSubdomainsBrute(context.Background(), "example.com", wordlist, 4, 0, 2, 0, "", "", checkpoint, resolver)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(report.Subdomains) != 3 {
+			t.Errorf("got subdomains %v, want the 3 subdomains of the checkpoint", report.Subdomains)
+		}
+	})
+
+	t.Run("rejects a checkpoint written with another wordlist", func(t *testing.T) {
+		checkpoint, err := LoadBruteCheckpoint(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := GetDomainSubdomainsBrute(context.Background(), "example.com", []string{"www", "api"}, 4, 0, 2, 0, "", "", checkpoint, resolver); err == nil {
+			t.Error("resumed from a checkpoint written with another wordlist, want an error")
+		}
+	})
+}
+
+// sorted returns a sorted copy of the names.
+func sorted(names []string) []string {
+	names = slices.Clone(names)
+	slices.Sort(names)
+	return names
+}
@@ -0,0 +1,307 @@
+package dns
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	osintscan "github.com/Method-Security/osintscan/generated/go"
+	"github.com/miekg/dns"
+)
+
+// AuditDomainDelegation compares the delegation of the domain published by its parent zone with the NS set the zone's
+// nameservers answer with authoritatively, and queries every address of every nameserver directly for the zone's SOA
+// and NS records. It returns a DelegationReport struct with findings for lame delegations, serial drift, missing glue,
+// nameservers that share a network or ASN and nameservers that offer recursion, along with any non-fatal errors that
+// occurred.
+func AuditDomainDelegation(ctx context.Context, domain string, resolver *Resolver) (osintscan.DelegationReport, error) {
+	errors := []string{}
+	zone := dns.CanonicalName(domain)
+	report := osintscan.DelegationReport{Domain: strings.TrimSuffix(zone, ".")}
+
+	zones, zoneErrors := findZoneCuts(ctx, resolver, zone)
+	errors = append(errors, zoneErrors...)
+	if len(zones) < 2 || dns.CanonicalName(zones[len(zones)-1]) != zone {
+		return report, fmt.Errorf("%s is not the apex of a zone", report.Domain)
+	}
+	parentZone := dns.CanonicalName(zones[len(zones)-2])
+	report.ParentZone = zones[len(zones)-2]
+
+	// The delegation is the referral the parent's servers hand out for the zone, along with any glue
+	parentResolver, err := authoritativeResolver(ctx, resolver, parentZone)
+	if err != nil {
+		return report, err
+	}
+	referral, err := walkQuery(ctx, parentResolver, zone, dns.TypeNS)
+	if err != nil {
+		return report, err
+	}
+	var parentNameservers []string
+	glue := map[string][]string{}
+	for _, rr := range append(referral.Answer, referral.Ns...) {
+		if ns, ok := rr.(*dns.NS); ok && dns.CanonicalName(ns.Hdr.Name) == zone && !slices.Contains(parentNameservers, dns.CanonicalName(ns.Ns)) {
+			parentNameservers = append(parentNameservers, dns.CanonicalName(ns.Ns))
+		}
+	}
+	for _, rr := range referral.Extra {
+		switch record := rr.(type) {
+		case *dns.A:
+			glue[dns.CanonicalName(record.Hdr.Name)] = append(glue[dns.CanonicalName(record.Hdr.Name)], record.A.String())
+		case *dns.AAAA:
+			glue[dns.CanonicalName(record.Hdr.Name)] = append(glue[dns.CanonicalName(record.Hdr.Name)], record.AAAA.String())
+		}
+	}
+	if len(parentNameservers) == 0 {
+		return report, fmt.Errorf("parent zone %s does not delegate %s", report.ParentZone, report.Domain)
+	}
+
+	// Every address of a nameserver is audited, whether the parent publishes it as glue or it resolves to it
+	audit := func(name string) *osintscan.DelegationNameserver {
+		nameserver := &osintscan.DelegationNameserver{
+			Name:        strings.TrimSuffix(name, "."),
+			InBailiwick: dns.IsSubDomain(zone, name),
+		}
+		addresses := append([]string{}, glue[name]...)
+		if resolved, err := resolver.LookupHost(ctx, name); err == nil {
+			for _, address := range resolved {
+				if !slices.Contains(addresses, address) {
+					addresses = append(addresses, address)
+				}
+			}
+		} else if len(addresses) == 0 {
+			errors = append(errors, fmt.Sprintf("could not resolve nameserver %s: %s", nameserver.Name, err.Error()))
+		}
+		for _, address := range addresses {
+			nameserverAddress := auditNameserver(ctx, resolver, zone, address)
+			nameserverAddress.Glue = slices.Contains(glue[name], address)
+			nameserver.Addresses = append(nameserver.Addresses, nameserverAddress)
+		}
+		return nameserver
+	}
+
+	// The zone's own NS set is the one its delegated nameservers answer with authoritatively
+	var childNameservers []string
+	for _, name := range parentNameservers {
+		nameserver := audit(name)
+		for _, address := range nameserver.Addresses {
+			for _, child := range address.Nameservers {
+				if child = dns.CanonicalName(child); !slices.Contains(childNameservers, child) {
+					childNameservers = append(childNameservers, child)
+				}
+			}
+		}
+		report.Nameservers = append(report.Nameservers, nameserver)
+	}
+	if len(childNameservers) == 0 {
+		errors = append(errors, fmt.Sprintf("no nameserver answered authoritatively with the NS set of %s", report.Domain))
+	}
+	for _, name := range childNameservers {
+		if !slices.Contains(parentNameservers, name) {
+			report.Nameservers = append(report.Nameservers, audit(name))
+		}
+	}
+
+	for _, name := range parentNameservers {
+		report.ParentNameservers = append(report.ParentNameservers, strings.TrimSuffix(name, "."))
+	}
+	for _, name := range childNameservers {
+		report.ChildNameservers = append(report.ChildNameservers, strings.TrimSuffix(name, "."))
+	}
+
+	var findings []string
+	for _, nameserver := range report.Nameservers {
+		name := dns.Fqdn(nameserver.Name)
+		nameserver.InParent = slices.Contains(parentNameservers, name)
+		nameserver.InChild = slices.Contains(childNameservers, name)
+		// Without an authoritative NS set there is nothing to compare the delegation with
+		switch {
+		case len(childNameservers) == 0:
+		case !nameserver.InChild:
+			findings = append(findings, fmt.Sprintf("%s is delegated to by the parent zone but missing from the zone's NS set", nameserver.Name))
+		case !nameserver.InParent:
+			findings = append(findings, fmt.Sprintf("%s is in the zone's NS set but missing from the parent delegation", nameserver.Name))
+		}
+
+		if nameserver.InBailiwick && nameserver.InParent && len(glue[name]) == 0 {
+			findings = append(findings, fmt.Sprintf("%s is inside the zone but the parent publishes no glue for it", nameserver.Name))
+		}
+		for _, address := range nameserver.Addresses {
+			if address.Lame {
+				reason := "it does not answer authoritatively for the zone"
+				if address.Error != nil {
+					reason = *address.Error
+				}
+				findings = append(findings, fmt.Sprintf("%s (%s) is a lame delegation: %s", nameserver.Name, address.Address, reason))
+			}
+			if address.RecursionAvailable {
+				findings = append(findings, fmt.Sprintf("%s (%s) answers with recursion available", nameserver.Name, address.Address))
+			}
+		}
+	}
+
+	report.Findings = append(findings, delegationFindings(report.Nameservers)...)
+	report.Errors = errors
+	return report, nil
+}
+
+// auditNameserver queries a single nameserver address directly, without recursion, for the SOA and NS records of the
+// zone and looks up the ASN that originates the address.
+func auditNameserver(ctx context.Context, resolver *Resolver, zone string, address string) *osintscan.DelegationNameserverAddress {
+	result := &osintscan.DelegationNameserverAddress{Address: address}
+	setError := func(err error) {
+		message := err.Error()
+		result.Error = &message
+		result.Lame = true
+	}
+
+	if asn, prefix, err := lookupASN(ctx, resolver, address); err == nil {
+		result.Asn = &asn
+		result.AsnPrefix = &prefix
+	}
+
+	server, err := parseUpstream(address, resolver.timeout)
+	if err != nil {
+		setError(err)
+		return result
+	}
+
+	msg := &dns.Msg{}
+	msg.SetQuestion(zone, dns.TypeSOA)
+	msg.RecursionDesired = false
+	msg.SetEdns0(4096, false)
+	resp, rtt, err := server.exchange(ctx, msg)
+	if err != nil {
+		setError(err)
+		return result
+	}
+	queryTime := int(rtt.Milliseconds())
+	rcode := dns.RcodeToString[resp.Rcode]
+	result.QueryTimeMs = &queryTime
+	result.Rcode = &rcode
+	result.Authoritative = resp.Authoritative
+	result.RecursionAvailable = resp.RecursionAvailable
+	for _, rr := range resp.Answer {
+		if soa, ok := rr.(*dns.SOA); ok && dns.CanonicalName(soa.Hdr.Name) == zone {
+			serial := int64(soa.Serial)
+			result.Serial = &serial
+		}
+	}
+	if resp.Rcode != dns.RcodeSuccess || !resp.Authoritative || result.Serial == nil {
+		result.Lame = true
+		return result
+	}
+
+	msg.SetQuestion(zone, dns.TypeNS)
+	if resp, _, err := server.exchange(ctx, msg); err == nil {
+		for _, rr := range resp.Answer {
+			if ns, ok := rr.(*dns.NS); ok && dns.CanonicalName(ns.Hdr.Name) == zone {
+				result.Nameservers = append(result.Nameservers, strings.TrimSuffix(dns.CanonicalName(ns.Ns), "."))
+			}
+		}
+		sort.Strings(result.Nameservers)
+	}
+	return result
+}
+
+// lookupASN returns the origin ASN and announced prefix of the address from the Team Cymru IP to ASN mapping service.
+func lookupASN(ctx context.Context, resolver *Resolver, address string) (int, string, error) {
+	reverse, err := dns.ReverseAddr(address)
+	if err != nil {
+		return 0, "", err
+	}
+	name := strings.TrimSuffix(reverse, "in-addr.arpa.") + "origin.asn.cymru.com."
+	if strings.HasSuffix(reverse, "ip6.arpa.") {
+		name = strings.TrimSuffix(reverse, "ip6.arpa.") + "origin6.asn.cymru.com."
+	}
+
+	records, err := queryRecords(ctx, resolver, name, dns.TypeTXT)
+	if err != nil {
+		return 0, "", err
+	}
+	for _, record := range records {
+		// "13335 | 1.1.1.0/24 | AU | apnic | 2011-08-11", where the first field may list several ASNs
+		fields := strings.Split(record.Value, "|")
+		if len(fields) < 2 {
+			continue
+		}
+		asns := strings.Fields(fields[0])
+		if len(asns) == 0 {
+			continue
+		}
+		asn, err := strconv.Atoi(asns[0])
+		if err != nil {
+			continue
+		}
+		return asn, strings.TrimSpace(fields[1]), nil
+	}
+	return 0, "", fmt.Errorf("%s: %w", name, ErrNoAnswer)
+}
+
+// delegationFindings reports problems that only show when the nameservers are compared with each other.
+func delegationFindings(nameservers []*osintscan.DelegationNameserver) []string {
+	var findings []string
+	if len(nameservers) < 2 {
+		findings = append(findings, "the zone has a single nameserver; at least two are required (RFC 1034 section 4.1)")
+	}
+
+	serials := map[int64][]string{}
+	nsSets := map[string][]string{}
+	networks := map[string]bool{}
+	asns := map[int]bool{}
+	addressCount := 0
+	for _, nameserver := range nameservers {
+		for _, address := range nameserver.Addresses {
+			addressCount++
+			label := fmt.Sprintf("%s (%s)", nameserver.Name, address.Address)
+			if address.Serial != nil {
+				serials[*address.Serial] = append(serials[*address.Serial], label)
+			}
+			if len(address.Nameservers) > 0 {
+				key := strings.Join(address.Nameservers, ", ")
+				nsSets[key] = append(nsSets[key], label)
+			}
+			if address.Asn != nil {
+				asns[*address.Asn] = true
+			}
+			if ip := net.ParseIP(address.Address); ip != nil {
+				if ip4 := ip.To4(); ip4 != nil {
+					networks[ip4.Mask(net.CIDRMask(24, 32)).String()+"/24"] = true
+				} else {
+					networks[ip.Mask(net.CIDRMask(48, 128)).String()+"/48"] = true
+				}
+			}
+		}
+	}
+
+	if len(serials) > 1 {
+		var drift []string
+		for serial, servers := range serials {
+			drift = append(drift, fmt.Sprintf("%d on %s", serial, strings.Join(servers, ", ")))
+		}
+		sort.Strings(drift)
+		findings = append(findings, fmt.Sprintf("SOA serials differ between nameservers: %s", strings.Join(drift, "; ")))
+	}
+	if len(nsSets) > 1 {
+		var sets []string
+		for set, servers := range nsSets {
+			sets = append(sets, fmt.Sprintf("[%s] on %s", set, strings.Join(servers, ", ")))
+		}
+		sort.Strings(sets)
+		findings = append(findings, fmt.Sprintf("nameservers return different NS sets: %s", strings.Join(sets, "; ")))
+	}
+	if addressCount > 1 && len(networks) == 1 {
+		for network := range networks {
+			findings = append(findings, fmt.Sprintf("every nameserver address is in %s, so a single network outage takes the zone offline", network))
+		}
+	}
+	if addressCount > 1 && len(asns) == 1 {
+		for asn := range asns {
+			findings = append(findings, fmt.Sprintf("every nameserver address is announced by AS%d, so a single provider outage takes the zone offline", asn))
+		}
+	}
+	return findings
+}
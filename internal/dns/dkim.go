@@ -0,0 +1,197 @@
+package dns
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	osintscan "github.com/Method-Security/osintscan/generated/go"
+	"github.com/miekg/dns"
+)
+
+const (
+	// dkimMinimumRSABits is the smallest RSA key verifiers must accept (RFC 8301 section 3.2)
+	dkimMinimumRSABits = 1024
+	// dkimRecommendedRSABits is the RSA key size signers should use (RFC 8301 section 3.2)
+	dkimRecommendedRSABits = 2048
+)
+
+// dkimSelectorResult holds the outcome of querying a single selector so results can be reassembled in wordlist order.
+type dkimSelectorResult struct {
+	records []*osintscan.DnsRecord
+	keys    []*osintscan.DkimKey
+	err     error
+}
+
+// discoverDKIM brute-forces DKIM selectors of the domain concurrently. It returns the raw TXT records of every selector
+// that published a DKIM key, the parsed keys and any non-fatal errors that occurred.
+func discoverDKIM(ctx context.Context, resolver *Resolver, domain string, selectors []string, parallelThreads int) ([]*osintscan.DnsRecord, []*osintscan.DkimKey, []string) {
+	selectors = normalizeSelectors(selectors)
+	if parallelThreads <= 0 {
+		parallelThreads = 1
+	}
+
+	results := make([]dkimSelectorResult, len(selectors))
+	semaphore := make(chan struct{}, parallelThreads)
+	var wg sync.WaitGroup
+	for i, selector := range selectors {
+		wg.Add(1)
+		go func(i int, selector string) {
+			defer wg.Done()
+
+			select {
+			case semaphore <- struct{}{}:
+				defer func() { <-semaphore }()
+			case <-ctx.Done():
+				results[i].err = ctx.Err()
+				return
+			}
+
+			records, err := queryRecords(ctx, resolver, selector+"._domainkey."+domain, dns.TypeTXT)
+			if err != nil {
+				results[i].err = err
+				return
+			}
+			for _, record := range records {
+				if !isDKIMRecord(record.Value) {
+					continue
+				}
+				results[i].records = append(results[i].records, record)
+				results[i].keys = append(results[i].keys, parseDKIMKey(selector, record))
+			}
+		}(i, selector)
+	}
+	wg.Wait()
+
+	var dkimRecords []*osintscan.DnsRecord
+	var dkimKeys []*osintscan.DkimKey
+	var errors []string
+	for _, result := range results {
+		if result.err != nil {
+			errors = append(errors, result.err.Error())
+			continue
+		}
+		dkimRecords = append(dkimRecords, result.records...)
+		dkimKeys = append(dkimKeys, result.keys...)
+	}
+	return dkimRecords, dkimKeys, errors
+}
+
+// normalizeSelectors lowercases and de-duplicates the selector list, dropping blank lines and comments.
+func normalizeSelectors(selectors []string) []string {
+	var normalized []string
+	for _, selector := range selectors {
+		selector = strings.ToLower(strings.TrimSpace(selector))
+		if selector == "" || strings.HasPrefix(selector, "#") || slices.Contains(normalized, selector) {
+			continue
+		}
+		normalized = append(normalized, selector)
+	}
+	return normalized
+}
+
+// isDKIMRecord reports whether the TXT record is a DKIM key record. The version tag is optional, but the public key tag
+// is required, even when empty for revoked keys.
+func isDKIMRecord(txt string) bool {
+	tags := parseTagList(txt)
+	if version, ok := tags["v"]; ok {
+		return strings.EqualFold(version, "DKIM1")
+	}
+	_, ok := tags["p"]
+	return ok
+}
+
+// parseDKIMKey parses a DKIM key record and flags revoked keys, test mode and weak RSA keys.
+func parseDKIMKey(selector string, record *osintscan.DnsRecord) *osintscan.DkimKey {
+	tags := parseTagList(record.Value)
+	key := &osintscan.DkimKey{
+		Selector: selector,
+		Domain:   record.Name,
+		Record:   record.Value,
+		K:        "rsa",
+	}
+	if value, ok := tags["v"]; ok {
+		key.V = &value
+	}
+	if value, ok := tags["k"]; ok && value != "" {
+		key.K = strings.ToLower(value)
+	}
+	key.T = splitDKIMList(tags["t"])
+	key.S = splitDKIMList(tags["s"])
+	key.H = splitDKIMList(tags["h"])
+
+	publicKey := strings.Join(strings.Fields(tags["p"]), "")
+	if publicKey == "" {
+		key.Revoked = true
+		key.Findings = append(key.Findings, "empty p= tag; the key has been revoked")
+	} else {
+		key.P = &publicKey
+		bits, err := dkimKeyLength(key.K, publicKey)
+		if err != nil {
+			key.Findings = append(key.Findings, err.Error())
+		} else {
+			key.KeyLength = &bits
+			if key.K == "rsa" && bits < dkimMinimumRSABits {
+				key.WeakKey = true
+				key.Findings = append(key.Findings, fmt.Sprintf("%d bit RSA key is below the %d bit minimum (RFC 8301)", bits, dkimMinimumRSABits))
+			} else if key.K == "rsa" && bits < dkimRecommendedRSABits {
+				key.Findings = append(key.Findings, fmt.Sprintf("%d bit RSA key is below the recommended %d bits (RFC 8301)", bits, dkimRecommendedRSABits))
+			}
+		}
+	}
+
+	if slices.Contains(key.T, "y") {
+		key.TestMode = true
+		key.Findings = append(key.Findings, "t=y marks the domain as testing DKIM; verifiers may ignore failures")
+	}
+	return key
+}
+
+func splitDKIMList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ":") {
+		item = strings.ToLower(strings.TrimSpace(item))
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// dkimKeyLength returns the size in bits of the base64 encoded public key. RSA keys are normally published as a
+// SubjectPublicKeyInfo structure, although some signers publish the bare PKCS#1 key instead.
+func dkimKeyLength(keyType string, publicKey string) (int, error) {
+	der, err := base64.StdEncoding.DecodeString(publicKey)
+	if err != nil {
+		return 0, fmt.Errorf("p= tag is not valid base64: %w", err)
+	}
+
+	switch keyType {
+	case "rsa":
+		if parsed, err := x509.ParsePKIXPublicKey(der); err == nil {
+			rsaKey, ok := parsed.(*rsa.PublicKey)
+			if !ok {
+				return 0, fmt.Errorf("k=rsa key holds a %T", parsed)
+			}
+			return rsaKey.N.BitLen(), nil
+		}
+		rsaKey, err := x509.ParsePKCS1PublicKey(der)
+		if err != nil {
+			return 0, fmt.Errorf("could not parse RSA public key: %w", err)
+		}
+		return rsaKey.N.BitLen(), nil
+	case "ed25519":
+		if len(der) != ed25519.PublicKeySize {
+			return 0, fmt.Errorf("ed25519 public key is %d bytes instead of %d", len(der), ed25519.PublicKeySize)
+		}
+		return ed25519.PublicKeySize * 8, nil
+	default:
+		return 0, fmt.Errorf("unknown key type %q", keyType)
+	}
+}
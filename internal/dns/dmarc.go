@@ -0,0 +1,262 @@
+package dns
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	osintscan "github.com/Method-Security/osintscan/generated/go"
+	"github.com/miekg/dns"
+	"github.com/weppos/publicsuffix-go/publicsuffix"
+)
+
+// isDMARCRecord reports whether the TXT record is a DMARC version 1 record.
+func isDMARCRecord(txt string) bool {
+	lower := strings.ToLower(strings.TrimSpace(txt))
+	return lower == "v=dmarc1" || strings.HasPrefix(lower, "v=dmarc1;") || strings.HasPrefix(lower, "v=dmarc1 ")
+}
+
+// organizationalDomain returns the registrable domain of the given name using the ICANN section of the public suffix
+// list, as required by RFC 7489 section 3.2. An empty string is returned when the name is itself a public suffix.
+func organizationalDomain(name string) string {
+	orgDomain, err := publicsuffix.DomainFromListWithOptions(publicsuffix.DefaultList, strings.TrimSuffix(strings.ToLower(name), "."), &publicsuffix.FindOptions{IgnorePrivate: true, DefaultRule: publicsuffix.DefaultRule})
+	if err != nil {
+		return ""
+	}
+	return orgDomain
+}
+
+// analyzeDMARC parses the DMARC policy published for the domain. When the domain publishes no policy, the policy of its
+// organizational domain applies instead (RFC 7489 section 6.6.3). It returns nil when neither publishes a policy, and a
+// policy without an effective policy when more than one record is published or receivers discard the record.
+func analyzeDMARC(ctx context.Context, resolver *Resolver, domain string, dmarcRecords []*osintscan.DnsRecord) *osintscan.DmarcPolicy {
+	orgDomain := organizationalDomain(domain)
+	policyDomain := domain
+	var findings []string
+
+	records := dmarcValues(dmarcRecords)
+	inherited := false
+	if len(records) == 0 && orgDomain != "" && !strings.EqualFold(orgDomain, domain) {
+		orgRecords, err := queryRecords(ctx, resolver, "_dmarc."+orgDomain, dns.TypeTXT)
+		if err != nil {
+			findings = append(findings, err.Error())
+		}
+		records = dmarcValues(orgRecords)
+		policyDomain = orgDomain
+		inherited = true
+	}
+	if len(records) == 0 {
+		return nil
+	}
+
+	policy := &osintscan.DmarcPolicy{
+		Domain:    policyDomain,
+		Record:    records[0],
+		Inherited: inherited,
+		Pct:       100,
+		Adkim:     "r",
+		Aspf:      "r",
+		Fo:        "0",
+	}
+	if orgDomain != "" {
+		policy.OrganizationalDomain = &orgDomain
+	}
+	// Receivers discard every record when more than one is published, so the domain has no policy at all
+	if len(records) > 1 {
+		policy.DuplicateRecords = true
+		findings = append(findings, fmt.Sprintf("%d DMARC records published for %s; receivers ignore all of them and apply no policy (RFC 7489 section 6.6.3)", len(records), policyDomain))
+		policy.Findings = findings
+		return policy
+	}
+
+	tags := parseTagList(records[0])
+	if value, ok := tags["p"]; ok {
+		policy.P = &value
+	}
+	if value, ok := tags["sp"]; ok {
+		policy.Sp = &value
+	}
+	if value, ok := tags["pct"]; ok {
+		pct, err := strconv.Atoi(value)
+		if err != nil || pct < 0 || pct > 100 {
+			findings = append(findings, fmt.Sprintf("invalid pct value %q", value))
+		} else {
+			policy.Pct = pct
+		}
+	}
+	if value, ok := tags["adkim"]; ok {
+		policy.Adkim = value
+	}
+	if value, ok := tags["aspf"]; ok {
+		policy.Aspf = value
+	}
+	if value, ok := tags["fo"]; ok {
+		policy.Fo = value
+	}
+	policy.Rua = dmarcDestinations(ctx, resolver, policyDomain, tags["rua"])
+	policy.Ruf = dmarcDestinations(ctx, resolver, policyDomain, tags["ruf"])
+
+	// Subdomains that inherit the organizational policy are governed by sp when it is present
+	effectivePolicy := policy.P
+	if inherited && policy.Sp != nil {
+		effectivePolicy = policy.Sp
+	}
+	// A record without a valid p tag, or with an invalid sp tag, is only applied as p=none when it has a report
+	// destination, and is discarded otherwise (RFC 7489 section 6.6.3)
+	invalid := ""
+	switch {
+	case policy.P == nil:
+		invalid = "missing p tag"
+	case !validDMARCPolicy(*policy.P):
+		invalid = fmt.Sprintf("invalid policy %q", *policy.P)
+	case policy.Sp != nil && !validDMARCPolicy(*policy.Sp):
+		invalid = fmt.Sprintf("invalid subdomain policy %q", *policy.Sp)
+	}
+	if invalid != "" {
+		if len(policy.Rua) == 0 {
+			findings = append(findings, invalid+" and no rua tag; receivers discard the record and apply no policy")
+			policy.Findings = findings
+			return policy
+		}
+		findings = append(findings, invalid+"; receivers treat the policy as none because rua is present")
+		none := "none"
+		effectivePolicy = &none
+	}
+	policy.EffectivePolicy = effectivePolicy
+
+	if *effectivePolicy == "none" {
+		findings = append(findings, "policy none only monitors and does not protect against spoofing")
+	}
+	if policy.Pct < 100 {
+		findings = append(findings, fmt.Sprintf("policy only applies to %d%% of failing mail", policy.Pct))
+	}
+	if len(policy.Rua) == 0 {
+		findings = append(findings, "no aggregate report destination (rua) configured")
+	}
+	for _, destination := range append(append([]*osintscan.DmarcReportDestination{}, policy.Rua...), policy.Ruf...) {
+		if destination.Authorized != nil && !*destination.Authorized {
+			findings = append(findings, fmt.Sprintf("external report destination %s has not authorized reports for %s (RFC 7489 section 7.1)", destination.Domain, policyDomain))
+		}
+	}
+
+	policy.Findings = findings
+	return policy
+}
+
+func dmarcValues(records []*osintscan.DnsRecord) []string {
+	var values []string
+	for _, record := range records {
+		if isDMARCRecord(record.Value) {
+			values = append(values, record.Value)
+		}
+	}
+	return values
+}
+
+// validDMARCPolicy reports whether the value of a p or sp tag is one of the policies defined by RFC 7489.
+func validDMARCPolicy(value string) bool {
+	return value == "none" || value == "quarantine" || value == "reject"
+}
+
+// dmarcDestinations parses a comma separated rua or ruf URI list. Destinations outside the policy's organizational
+// domain must publish a <policy-domain>._report._dmarc.<destination> record to accept reports (RFC 7489 section 7.1).
+func dmarcDestinations(ctx context.Context, resolver *Resolver, policyDomain string, uris string) []*osintscan.DmarcReportDestination {
+	var destinations []*osintscan.DmarcReportDestination
+	for _, uri := range strings.Split(uris, ",") {
+		uri = strings.TrimSpace(uri)
+		if uri == "" {
+			continue
+		}
+
+		// A trailing !<size> limits the report size and is not part of the URI
+		target := uri
+		if i := strings.LastIndex(target, "!"); i > 0 {
+			target = target[:i]
+		}
+		destinationDomain := ""
+		if parsed, err := url.Parse(target); err == nil {
+			if parsed.Scheme == "mailto" {
+				if at := strings.LastIndex(parsed.Opaque, "@"); at >= 0 {
+					destinationDomain = parsed.Opaque[at+1:]
+				}
+			} else {
+				destinationDomain = parsed.Hostname()
+			}
+		}
+		destinationDomain = strings.ToLower(destinationDomain)
+
+		destination := &osintscan.DmarcReportDestination{
+			Uri:      uri,
+			Domain:   destinationDomain,
+			External: destinationDomain != "" && !strings.EqualFold(organizationalDomain(destinationDomain), organizationalDomain(policyDomain)),
+		}
+		if destination.External {
+			authorized := false
+			records, err := queryRecords(ctx, resolver, policyDomain+"._report._dmarc."+destinationDomain, dns.TypeTXT)
+			if err == nil {
+				for _, record := range records {
+					if isDMARCRecord(record.Value) {
+						authorized = true
+						value := record.Value
+						destination.AuthorizationRecord = &value
+						break
+					}
+				}
+				destination.Authorized = &authorized
+			}
+		}
+		destinations = append(destinations, destination)
+	}
+	return destinations
+}
+
+// assessSpoofability combines the SPF and DMARC posture of a domain into a verdict on whether mail claiming to be from
+// it can be forged. SPF alone only protects the envelope sender; it is DMARC enforcement that protects the visible From.
+func assessSpoofability(spf *osintscan.SpfPolicy, dmarc *osintscan.DmarcPolicy) *osintscan.SpoofabilityAssessment {
+	var reasons []string
+
+	spfWeak := false
+	switch {
+	case spf == nil || spf.RecordCount == 0:
+		spfWeak = true
+		reasons = append(reasons, "no SPF record is published")
+	case spf.DuplicateRecords || spf.LookupLimitExceeded || spf.VoidLookupLimitExceeded:
+		spfWeak = true
+		reasons = append(reasons, "the SPF policy evaluates to a permerror")
+	case spf.PermissiveAll:
+		spfWeak = true
+		reasons = append(reasons, "the SPF policy does not fail unauthorized senders")
+	}
+
+	weakVerdict := osintscan.SpoofabilityVerdictPossiblySpoofable
+	if spfWeak {
+		weakVerdict = osintscan.SpoofabilityVerdictSpoofable
+	}
+
+	switch {
+	case dmarc == nil:
+		reasons = append(reasons, "no DMARC policy is published, so the From header is not protected")
+		return &osintscan.SpoofabilityAssessment{Verdict: weakVerdict, Reasons: reasons}
+	case dmarc.DuplicateRecords:
+		reasons = append(reasons, "multiple DMARC records are published, so receivers apply no policy and the From header is not protected")
+		return &osintscan.SpoofabilityAssessment{Verdict: weakVerdict, Reasons: reasons}
+	case dmarc.EffectivePolicy == nil:
+		reasons = append(reasons, "the DMARC record is invalid, so receivers apply no policy and the From header is not protected")
+		return &osintscan.SpoofabilityAssessment{Verdict: weakVerdict, Reasons: reasons}
+	}
+
+	switch *dmarc.EffectivePolicy {
+	case "quarantine", "reject":
+		if dmarc.Pct < 100 {
+			reasons = append(reasons, fmt.Sprintf("DMARC %s only applies to %d%% of failing mail", *dmarc.EffectivePolicy, dmarc.Pct))
+			return &osintscan.SpoofabilityAssessment{Verdict: osintscan.SpoofabilityVerdictPossiblySpoofable, Reasons: reasons}
+		}
+		reasons = append(reasons, fmt.Sprintf("DMARC %s is enforced on all failing mail", *dmarc.EffectivePolicy))
+		return &osintscan.SpoofabilityAssessment{Verdict: osintscan.SpoofabilityVerdictNotSpoofable, Reasons: reasons}
+	default:
+		reasons = append(reasons, "the DMARC policy is none, so failing mail is still delivered")
+		return &osintscan.SpoofabilityAssessment{Verdict: weakVerdict, Reasons: reasons}
+	}
+}
@@ -0,0 +1,186 @@
+package dns
+
+import (
+	"context"
+	"testing"
+
+	osintscan "github.com/Method-Security/osintscan/generated/go"
+)
+
+func TestAnalyzeDMARC(t *testing.T) {
+	resolver := startZoneServer(t,
+		`_dmarc.example.com. 300 IN TXT "v=DMARC1; p=reject; sp=quarantine; rua=mailto:dmarc@example.com,mailto:dmarc@reports.example.net"`,
+		`example.com._report._dmarc.reports.example.net. 300 IN TXT "v=DMARC1"`,
+		`_dmarc.example.org. 300 IN TXT "v=DMARC1; p=quarantine; pct=50"`,
+	)
+
+	tests := []struct {
+		name       string
+		domain     string
+		own        []string
+		noPolicy   bool
+		domainWant string
+		inherited  bool
+		duplicate  bool
+		effective  string
+		pct        int
+	}{
+		{
+			name:       "own record",
+			domain:     "example.com",
+			own:        []string{"v=DMARC1; p=none; rua=mailto:dmarc@example.com"},
+			domainWant: "example.com",
+			effective:  "none",
+			pct:        100,
+		},
+		{
+			name:       "subdomain falls back to the organizational sp",
+			domain:     "mail.example.com",
+			domainWant: "example.com",
+			inherited:  true,
+			effective:  "quarantine",
+			pct:        100,
+		},
+		{
+			name:       "subdomain falls back to the organizational p without sp",
+			domain:     "mail.example.org",
+			domainWant: "example.org",
+			inherited:  true,
+			effective:  "quarantine",
+			pct:        50,
+		},
+		{
+			name:       "own record takes precedence over the organizational domain",
+			domain:     "mail.example.com",
+			own:        []string{"v=DMARC1; p=none; rua=mailto:dmarc@example.com"},
+			domainWant: "mail.example.com",
+			effective:  "none",
+			pct:        100,
+		},
+		{
+			name:     "no record anywhere",
+			domain:   "mail.example.net",
+			noPolicy: true,
+		},
+		{
+			name:       "multiple records apply no policy",
+			domain:     "example.com",
+			own:        []string{"v=DMARC1; p=reject", "v=DMARC1; p=none"},
+			domainWant: "example.com",
+			duplicate:  true,
+			pct:        100,
+		},
+		{
+			name:       "missing p with rua is none",
+			domain:     "example.com",
+			own:        []string{"v=DMARC1; rua=mailto:dmarc@example.com"},
+			domainWant: "example.com",
+			effective:  "none",
+			pct:        100,
+		},
+		{
+			name:       "missing p without rua is discarded",
+			domain:     "example.com",
+			own:        []string{"v=DMARC1; pct=100"},
+			domainWant: "example.com",
+			pct:        100,
+		},
+		{
+			name:       "invalid p without rua is discarded",
+			domain:     "example.com",
+			own:        []string{"v=DMARC1; p=block"},
+			domainWant: "example.com",
+			pct:        100,
+		},
+		{
+			name:       "records that are not DMARC are ignored",
+			domain:     "example.com",
+			own:        []string{"v=spf1 -all", "v=DMARC1; p=reject"},
+			domainWant: "example.com",
+			effective:  "reject",
+			pct:        100,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var records []*osintscan.DnsRecord
+			for _, value := range test.own {
+				records = append(records, &osintscan.DnsRecord{Name: "_dmarc." + test.domain, Value: value})
+			}
+			policy := analyzeDMARC(context.Background(), resolver, test.domain, records)
+			if test.noPolicy {
+				if policy != nil {
+					t.Fatalf("got a policy from %s, want none", policy.Domain)
+				}
+				return
+			}
+			if policy == nil {
+				t.Fatal("got no policy")
+			}
+			if policy.Domain != test.domainWant || policy.Inherited != test.inherited || policy.DuplicateRecords != test.duplicate {
+				t.Errorf("got domain %s, inherited %t, duplicate %t, want %s, %t, %t", policy.Domain, policy.Inherited, policy.DuplicateRecords, test.domainWant, test.inherited, test.duplicate)
+			}
+			effective := ""
+			if policy.EffectivePolicy != nil {
+				effective = *policy.EffectivePolicy
+			}
+			if effective != test.effective || policy.Pct != test.pct {
+				t.Errorf("got effective policy %q at %d%%, want %q at %d%%", effective, policy.Pct, test.effective, test.pct)
+			}
+		})
+	}
+
+	t.Run("external report destinations are checked for authorization", func(t *testing.T) {
+		policy := analyzeDMARC(context.Background(), resolver, "mail.example.com", nil)
+		if policy == nil || len(policy.Rua) != 2 {
+			t.Fatalf("got policy %+v, want two rua destinations", policy)
+		}
+		internal, external := policy.Rua[0], policy.Rua[1]
+		if internal.External || internal.Authorized != nil {
+			t.Errorf("got %s external %t, want an internal destination that is not checked", internal.Uri, internal.External)
+		}
+		if !external.External || external.Authorized == nil || !*external.Authorized {
+			t.Errorf("got %s external %t authorized %v, want an authorized external destination", external.Uri, external.External, external.Authorized)
+		}
+	})
+}
+
+func TestAssessSpoofability(t *testing.T) {
+	strictSPF := &osintscan.SpfPolicy{RecordCount: 1, AllQualifier: osintscan.SpfQualifierFail.Ptr()}
+	weakSPF := &osintscan.SpfPolicy{RecordCount: 1, AllQualifier: osintscan.SpfQualifierNeutral.Ptr(), PermissiveAll: true}
+	brokenSPF := &osintscan.SpfPolicy{RecordCount: 1, LookupCount: 11, LookupLimitExceeded: true}
+	dmarc := func(effective string, pct int) *osintscan.DmarcPolicy {
+		policy := &osintscan.DmarcPolicy{Pct: pct}
+		if effective != "" {
+			policy.EffectivePolicy = &effective
+		}
+		return policy
+	}
+	duplicate := dmarc("", 100)
+	duplicate.DuplicateRecords = true
+
+	tests := []struct {
+		name    string
+		spf     *osintscan.SpfPolicy
+		dmarc   *osintscan.DmarcPolicy
+		verdict osintscan.SpoofabilityVerdict
+	}{
+		{name: "reject on all mail", spf: strictSPF, dmarc: dmarc("reject", 100), verdict: osintscan.SpoofabilityVerdictNotSpoofable},
+		{name: "quarantine protects despite a broken SPF policy", spf: brokenSPF, dmarc: dmarc("quarantine", 100), verdict: osintscan.SpoofabilityVerdictNotSpoofable},
+		{name: "partial enforcement", spf: strictSPF, dmarc: dmarc("reject", 50), verdict: osintscan.SpoofabilityVerdictPossiblySpoofable},
+		{name: "no DMARC with strict SPF", spf: strictSPF, verdict: osintscan.SpoofabilityVerdictPossiblySpoofable},
+		{name: "no DMARC and no SPF", verdict: osintscan.SpoofabilityVerdictSpoofable},
+		{name: "policy none with weak SPF", spf: weakSPF, dmarc: dmarc("none", 100), verdict: osintscan.SpoofabilityVerdictSpoofable},
+		{name: "policy none with a broken SPF policy", spf: brokenSPF, dmarc: dmarc("none", 100), verdict: osintscan.SpoofabilityVerdictSpoofable},
+		{name: "duplicate DMARC records", spf: strictSPF, dmarc: duplicate, verdict: osintscan.SpoofabilityVerdictPossiblySpoofable},
+		{name: "discarded DMARC record", spf: weakSPF, dmarc: dmarc("", 100), verdict: osintscan.SpoofabilityVerdictSpoofable},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assessment := assessSpoofability(test.spf, test.dmarc)
+			if assessment.Verdict != test.verdict {
+				t.Errorf("got %s %v, want %s", assessment.Verdict, assessment.Reasons, test.verdict)
+			}
+		})
+	}
+}
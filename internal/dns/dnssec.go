@@ -0,0 +1,428 @@
+package dns
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"time"
+
+	osintscan "github.com/Method-Security/osintscan/generated/go"
+	"github.com/miekg/dns"
+)
+
+// dnssecExpiryWarning is how close to expiry a signature must be before it is reported. Zones normally re-sign well
+// before this, so a signature this close to expiring usually means the signer has stopped.
+const dnssecExpiryWarning = 7 * 24 * time.Hour
+
+// rootTrustAnchors are the DS records of the root zone key signing keys published by IANA
+// (https://data.iana.org/root-anchors/root-anchors.xml).
+var rootTrustAnchors = []string{
+	". IN DS 20326 8 2 E06D44B80B8F1D39A95C0B0D7C65D08458E880409BBC683457104237C7F8EC8D",
+	". IN DS 38696 8 2 683D2D0ACB8C9B712A1948B27F741219298D0A450D612C483AF444A4C0FB2B16",
+}
+
+// deprecatedDNSSECAlgorithms are the signing algorithms RFC 8624 section 3.1 says must not or should not be used.
+var deprecatedDNSSECAlgorithms = []uint8{dns.RSAMD5, dns.DSA, dns.RSASHA1, dns.DSANSEC3SHA1, dns.RSASHA1NSEC3SHA1, dns.ECCGOST}
+
+// ValidateDomainDNSSEC walks the DNSSEC chain of trust from the root trust anchor down to the zone of the given domain.
+// At every zone cut it checks that the parent's DS records match a DNSKEY of the child and that the DNSKEY, DS and SOA
+// RRsets carry valid signatures. It returns a DnssecReport struct with the status of every zone and the first link of
+// the chain that is broken, along with any non-fatal errors that occurred.
+func ValidateDomainDNSSEC(ctx context.Context, domain string, resolver *Resolver) (osintscan.DnssecReport, error) {
+	errors := []string{}
+	report := osintscan.DnssecReport{
+		Domain: domain,
+		Status: osintscan.DnssecStatusSecure,
+	}
+
+	zones, zoneErrors := findZoneCuts(ctx, resolver, domain)
+	errors = append(errors, zoneErrors...)
+
+	var anchors []*dns.DS
+	for _, anchor := range rootTrustAnchors {
+		rr, err := dns.NewRR(anchor)
+		if err != nil {
+			return report, err
+		}
+		anchors = append(anchors, rr.(*dns.DS))
+	}
+
+	now := time.Now()
+	var parentKeys []*dns.DNSKEY
+	for i, zone := range zones {
+		zoneReport := &osintscan.DnssecZone{
+			Zone:   zone,
+			Status: report.Status,
+		}
+		bogus := func(format string, args ...any) {
+			finding := fmt.Sprintf(format, args...)
+			zoneReport.Findings = append(zoneReport.Findings, finding)
+			zoneReport.Status = osintscan.DnssecStatusBogus
+			if report.Status != osintscan.DnssecStatusBogus {
+				report.Status = osintscan.DnssecStatusBogus
+				brokenLink := zone
+				report.BrokenLink = &brokenLink
+			}
+		}
+
+		// The parent's DS RRset links the zone into the chain; the root is anchored by the trust anchors instead
+		dsSet := anchors
+		if i > 0 {
+			resp, err := dnssecQuery(ctx, resolver, zone, dns.TypeDS)
+			if err != nil {
+				errors = append(errors, err.Error())
+				bogus("could not query the DS records of %s: %s", zone, err.Error())
+			} else {
+				var dsRRset []dns.RR
+				dsSet, dsRRset = dsRecords(resp, zone)
+				if len(dsSet) > 0 && len(parentKeys) > 0 {
+					signatures, valid := verifyRRset(dsRRset, rrsigs(resp.Answer, zone, dns.TypeDS), parentKeys, now)
+					zoneReport.Signatures = append(zoneReport.Signatures, signatures...)
+					if !valid {
+						bogus("the DS RRset of %s is not validly signed by its parent zone", zone)
+					}
+				}
+			}
+		}
+		for _, ds := range dsSet {
+			zoneReport.Ds = append(zoneReport.Ds, dnsRecordFromRR(ds).Ds)
+			if ds.DigestType == dns.SHA1 || ds.DigestType == dns.GOST94 {
+				zoneReport.Findings = append(zoneReport.Findings, fmt.Sprintf("DS record %d uses the deprecated %s digest (RFC 8624 section 3.3)", ds.KeyTag, dns.HashToString[ds.DigestType]))
+			}
+		}
+
+		resp, err := dnssecQuery(ctx, resolver, zone, dns.TypeDNSKEY)
+		if err != nil {
+			errors = append(errors, err.Error())
+		}
+		var keys []*dns.DNSKEY
+		var keyRRset []dns.RR
+		if resp != nil {
+			for _, rr := range resp.Answer {
+				if key, ok := rr.(*dns.DNSKEY); ok && strings.EqualFold(key.Hdr.Name, dns.Fqdn(zone)) {
+					keys = append(keys, key)
+					keyRRset = append(keyRRset, key)
+				}
+			}
+		}
+
+		switch {
+		case len(dsSet) == 0 && len(keys) == 0:
+			// An unsigned delegation is insecure rather than broken
+			if zoneReport.Status == osintscan.DnssecStatusSecure {
+				zoneReport.Status = osintscan.DnssecStatusInsecure
+				report.Status = osintscan.DnssecStatusInsecure
+			}
+			zoneReport.Findings = append(zoneReport.Findings, "zone is not signed")
+		case len(dsSet) == 0:
+			if zoneReport.Status == osintscan.DnssecStatusSecure {
+				zoneReport.Status = osintscan.DnssecStatusInsecure
+				report.Status = osintscan.DnssecStatusInsecure
+				brokenLink := zone
+				report.BrokenLink = &brokenLink
+			}
+			zoneReport.Findings = append(zoneReport.Findings, "zone publishes DNSKEY records but its parent has no DS record, so validators treat it as unsigned")
+		case len(keys) == 0:
+			bogus("parent publishes DS records but the zone has no DNSKEY records")
+		}
+
+		trusted := trustedKeys(keys, dsSet)
+		for _, key := range keys {
+			zoneReport.Keys = append(zoneReport.Keys, dnssecKey(key, slices.Contains(trusted, key)))
+			if slices.Contains(deprecatedDNSSECAlgorithms, key.Algorithm) {
+				zoneReport.Findings = append(zoneReport.Findings, fmt.Sprintf("DNSKEY %d uses the deprecated %s algorithm (RFC 8624 section 3.1)", key.KeyTag(), dns.AlgorithmToString[key.Algorithm]))
+			}
+		}
+		for _, key := range zoneReport.Keys {
+			if key.KeySize != nil && strings.HasPrefix(key.AlgorithmName, "RSA") && *key.KeySize < 2048 {
+				zoneReport.Findings = append(zoneReport.Findings, fmt.Sprintf("DNSKEY %d is a %d bit RSA key; 2048 bits or more is recommended", key.KeyTag, *key.KeySize))
+			}
+		}
+
+		if len(keys) > 0 {
+			if len(dsSet) > 0 && len(trusted) == 0 {
+				bogus("no DNSKEY matches the DS records published by the parent zone")
+			}
+			// Every signature is checked against the whole key set, but only one made by a trusted key links the chain
+			signatures, _ := verifyRRset(keyRRset, rrsigs(resp.Answer, zone, dns.TypeDNSKEY), keys, now)
+			zoneReport.Signatures = append(zoneReport.Signatures, signatures...)
+			valid := slices.ContainsFunc(signatures, func(signature *osintscan.DnssecSignature) bool {
+				return signature.Valid && slices.ContainsFunc(trusted, func(key *dns.DNSKEY) bool { return int(key.KeyTag()) == signature.KeyTag })
+			})
+			if len(trusted) > 0 && !valid {
+				bogus("the DNSKEY RRset is not validly signed by a key matching the parent's DS records")
+			}
+
+			// The SOA RRset is signed by the zone signing key, which completes the chain to the zone's own data
+			soaResp, err := dnssecQuery(ctx, resolver, zone, dns.TypeSOA)
+			if err != nil {
+				errors = append(errors, err.Error())
+			} else {
+				var soaRRset []dns.RR
+				for _, rr := range soaResp.Answer {
+					if rr.Header().Rrtype == dns.TypeSOA && strings.EqualFold(rr.Header().Name, dns.Fqdn(zone)) {
+						soaRRset = append(soaRRset, rr)
+					}
+				}
+				signatures, valid := verifyRRset(soaRRset, rrsigs(soaResp.Answer, zone, dns.TypeSOA), keys, now)
+				zoneReport.Signatures = append(zoneReport.Signatures, signatures...)
+				if !valid && zoneReport.Status == osintscan.DnssecStatusSecure {
+					bogus("the SOA RRset is not validly signed by a key in the DNSKEY RRset")
+				}
+			}
+
+			denial, findings, err := dnssecDenial(ctx, resolver, zone)
+			if err != nil {
+				errors = append(errors, err.Error())
+			}
+			zoneReport.Denial = denial
+			zoneReport.Findings = append(zoneReport.Findings, findings...)
+		}
+
+		for _, signature := range zoneReport.Signatures {
+			if signature.Error != nil {
+				zoneReport.Findings = append(zoneReport.Findings, fmt.Sprintf("RRSIG over %s by key %d: %s", signature.TypeCovered, signature.KeyTag, *signature.Error))
+			} else if signature.Expiration.Sub(now) < dnssecExpiryWarning {
+				zoneReport.Findings = append(zoneReport.Findings, fmt.Sprintf("RRSIG over %s by key %d expires on %s", signature.TypeCovered, signature.KeyTag, signature.Expiration.Format(time.RFC3339)))
+			}
+		}
+
+		report.Zones = append(report.Zones, zoneReport)
+		parentKeys = keys
+	}
+
+	report.Errors = errors
+	return report, nil
+}
+
+// findZoneCuts returns the zones that make up the path from the root to the zone containing the domain, root first.
+// A name is a zone apex when it owns an SOA record.
+func findZoneCuts(ctx context.Context, resolver *Resolver, domain string) ([]string, []string) {
+	zones := []string{"."}
+	var errors []string
+
+	labels := dns.SplitDomainName(domain)
+	for i := len(labels) - 1; i >= 0; i-- {
+		name := dns.Fqdn(strings.Join(labels[i:], "."))
+		resp, err := dnssecQuery(ctx, resolver, name, dns.TypeSOA)
+		if err != nil {
+			errors = append(errors, err.Error())
+			continue
+		}
+		if resp.Rcode == dns.RcodeNameError {
+			errors = append(errors, fmt.Sprintf("%s does not exist", name))
+			break
+		}
+		for _, rr := range resp.Answer {
+			if rr.Header().Rrtype == dns.TypeSOA && strings.EqualFold(rr.Header().Name, name) {
+				zones = append(zones, strings.TrimSuffix(name, "."))
+				break
+			}
+		}
+	}
+	return zones, errors
+}
+
+// dnssecQuery sends a query with the DO bit set so signatures are returned, and the CD bit set so that a validating
+// resolver still hands back data that fails validation instead of answering SERVFAIL.
+func dnssecQuery(ctx context.Context, resolver *Resolver, name string, questionType uint16) (*dns.Msg, error) {
+	msg := &dns.Msg{}
+	msg.SetQuestion(dns.Fqdn(name), questionType)
+	msg.SetEdns0(4096, true)
+	msg.CheckingDisabled = true
+	resp, err := resolver.Exchange(ctx, msg)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Msg, nil
+}
+
+func dsRecords(resp *dns.Msg, zone string) ([]*dns.DS, []dns.RR) {
+	var dsSet []*dns.DS
+	var rrset []dns.RR
+	for _, rr := range resp.Answer {
+		if ds, ok := rr.(*dns.DS); ok && strings.EqualFold(ds.Hdr.Name, dns.Fqdn(zone)) {
+			dsSet = append(dsSet, ds)
+			rrset = append(rrset, ds)
+		}
+	}
+	return dsSet, rrset
+}
+
+func rrsigs(rrs []dns.RR, name string, typeCovered uint16) []*dns.RRSIG {
+	var signatures []*dns.RRSIG
+	for _, rr := range rrs {
+		if signature, ok := rr.(*dns.RRSIG); ok && signature.TypeCovered == typeCovered && strings.EqualFold(signature.Hdr.Name, dns.Fqdn(name)) {
+			signatures = append(signatures, signature)
+		}
+	}
+	return signatures
+}
+
+// trustedKeys returns the DNSKEYs whose digest matches one of the DS records.
+func trustedKeys(keys []*dns.DNSKEY, dsSet []*dns.DS) []*dns.DNSKEY {
+	var trusted []*dns.DNSKEY
+	for _, key := range keys {
+		for _, ds := range dsSet {
+			if key.KeyTag() != ds.KeyTag || key.Algorithm != ds.Algorithm {
+				continue
+			}
+			if keyDS := key.ToDS(ds.DigestType); keyDS != nil && strings.EqualFold(keyDS.Digest, ds.Digest) {
+				trusted = append(trusted, key)
+				break
+			}
+		}
+	}
+	return trusted
+}
+
+// verifyRRset checks every signature over the RRset against the given keys. The RRset is valid when at least one
+// signature made by one of the keys verifies and is within its validity period.
+func verifyRRset(rrset []dns.RR, signatures []*dns.RRSIG, keys []*dns.DNSKEY, now time.Time) ([]*osintscan.DnssecSignature, bool) {
+	var results []*osintscan.DnssecSignature
+	valid := false
+	if len(rrset) == 0 {
+		return results, false
+	}
+	for _, signature := range signatures {
+		result := &osintscan.DnssecSignature{
+			Name:          strings.TrimSuffix(signature.Hdr.Name, "."),
+			TypeCovered:   dns.TypeToString[signature.TypeCovered],
+			KeyTag:        int(signature.KeyTag),
+			SignerName:    signature.SignerName,
+			Algorithm:     int(signature.Algorithm),
+			AlgorithmName: dns.AlgorithmToString[signature.Algorithm],
+			Inception:     rrsigTime(signature.Inception, now),
+			Expiration:    rrsigTime(signature.Expiration, now),
+		}
+
+		var verifyErr error = fmt.Errorf("no DNSKEY with key tag %d", signature.KeyTag)
+		for _, key := range keys {
+			if key.KeyTag() != signature.KeyTag || key.Algorithm != signature.Algorithm {
+				continue
+			}
+			if verifyErr = signature.Verify(key, rrset); verifyErr == nil {
+				break
+			}
+		}
+		switch {
+		case verifyErr != nil:
+			message := verifyErr.Error()
+			result.Error = &message
+		case !signature.ValidityPeriod(now):
+			message := "signature is outside its validity period"
+			result.Error = &message
+		default:
+			result.Valid = true
+			valid = true
+		}
+		results = append(results, result)
+	}
+	return results, valid
+}
+
+// rrsigTime converts an RRSIG timestamp using serial number arithmetic (RFC 4034 section 3.1.5), picking the time
+// closest to now.
+func rrsigTime(timestamp uint32, now time.Time) time.Time {
+	modulo := int64(1) << 32
+	offset := (int64(timestamp) - now.Unix()) % modulo
+	if offset < -modulo/2 {
+		offset += modulo
+	} else if offset > modulo/2 {
+		offset -= modulo
+	}
+	return now.Add(time.Duration(offset) * time.Second).UTC().Truncate(time.Second)
+}
+
+func dnssecKey(key *dns.DNSKEY, trusted bool) *osintscan.DnssecKey {
+	return &osintscan.DnssecKey{
+		KeyTag:           int(key.KeyTag()),
+		Flags:            int(key.Flags),
+		Algorithm:        int(key.Algorithm),
+		AlgorithmName:    dns.AlgorithmToString[key.Algorithm],
+		KeySize:          dnssecKeySize(key),
+		SecureEntryPoint: key.Flags&dns.SEP != 0,
+		Revoked:          key.Flags&dns.REVOKE != 0,
+		Trusted:          trusted,
+	}
+}
+
+// dnssecKeySize returns the size in bits of the DNSKEY's public key, or nil for unknown algorithms.
+func dnssecKeySize(key *dns.DNSKEY) *int {
+	var size int
+	switch key.Algorithm {
+	case dns.RSAMD5, dns.RSASHA1, dns.RSASHA1NSEC3SHA1, dns.RSASHA256, dns.RSASHA512:
+		// RFC 3110 section 2: exponent length, exponent, modulus
+		publicKey, err := base64.StdEncoding.DecodeString(key.PublicKey)
+		if err != nil || len(publicKey) < 3 {
+			return nil
+		}
+		exponentLength, offset := int(publicKey[0]), 1
+		if exponentLength == 0 {
+			exponentLength, offset = int(publicKey[1])<<8|int(publicKey[2]), 3
+		}
+		if offset+exponentLength >= len(publicKey) {
+			return nil
+		}
+		size = new(big.Int).SetBytes(publicKey[offset+exponentLength:]).BitLen()
+	case dns.ECDSAP256SHA256, dns.ED25519:
+		size = 256
+	case dns.ECDSAP384SHA384:
+		size = 384
+	case dns.ED448:
+		size = 456
+	default:
+		return nil
+	}
+	return &size
+}
+
+// dnssecDenial queries a name that cannot exist in the zone and reports how the zone proves its non-existence.
+func dnssecDenial(ctx context.Context, resolver *Resolver, zone string) (*osintscan.DnssecDenial, []string, error) {
+	name := fmt.Sprintf("osintscan-%x.%s", rand.Uint64(), zone)
+	if zone == "." {
+		name = fmt.Sprintf("osintscan-%x.", rand.Uint64())
+	}
+	resp, err := dnssecQuery(ctx, resolver, name, dns.TypeA)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var findings []string
+	for _, rr := range resp.Ns {
+		switch record := rr.(type) {
+		case *dns.NSEC:
+			findings = append(findings, "NSEC records allow the zone contents to be enumerated by walking the chain")
+			return &osintscan.DnssecDenial{Type: osintscan.DnssecDenialTypeNsec}, findings, nil
+		case *dns.NSEC3:
+			iterations := int(record.Iterations)
+			hashAlgorithm := int(record.Hash)
+			optOut := record.Flags&1 != 0
+			salt := record.Salt
+			if salt == "-" {
+				salt = ""
+			}
+			if iterations > 0 {
+				findings = append(findings, fmt.Sprintf("NSEC3 uses %d additional iterations; RFC 9276 recommends 0", iterations))
+			}
+			if salt != "" {
+				findings = append(findings, "NSEC3 uses a salt; RFC 9276 recommends an empty salt")
+			}
+			if optOut {
+				findings = append(findings, "NSEC3 opt-out is set, so unsigned delegations are not covered by the denial of existence proof")
+			}
+			return &osintscan.DnssecDenial{
+				Type:               osintscan.DnssecDenialTypeNsec3,
+				Nsec3HashAlgorithm: &hashAlgorithm,
+				Nsec3Iterations:    &iterations,
+				Nsec3Salt:          &salt,
+				Nsec3OptOut:        &optOut,
+			}, findings, nil
+		}
+	}
+	return nil, []string{"negative answers carry no NSEC or NSEC3 records, so non-existence cannot be proven"}, nil
+}
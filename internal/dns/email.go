@@ -0,0 +1,344 @@
+package dns
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	osintscan "github.com/Method-Security/osintscan/generated/go"
+	"github.com/miekg/dns"
+)
+
+// mtaStsPolicyMaxSize bounds how much of an MTA-STS policy file is read. Legitimate policies are a few hundred bytes.
+const mtaStsPolicyMaxSize = 64 * 1024
+
+// GetDomainEmailSecurity checks the transport and brand email-security controls of a domain: MTA-STS, TLS-RPT, BIMI and
+// DANE for each of its MX hosts. It returns an EmailSecurityReport struct with a pass/fail status per control and any
+// non-fatal errors that occurred.
+func GetDomainEmailSecurity(ctx context.Context, domain string, resolver *Resolver, timeout int) (osintscan.EmailSecurityReport, error) {
+	errors := []string{}
+
+	httpClient := &http.Client{
+		Timeout: time.Duration(timeout) * time.Second,
+	}
+
+	mxRecords, err := queryRecords(ctx, resolver, domain, dns.TypeMX)
+	if err != nil {
+		errors = append(errors, err.Error())
+	}
+
+	report := osintscan.EmailSecurityReport{
+		Domain: domain,
+		MtaSts: checkMtaSts(ctx, resolver, httpClient, domain, mxRecords),
+		TlsRpt: checkTlsRpt(ctx, resolver, domain),
+		Bimi:   checkBimi(ctx, resolver, httpClient, domain),
+		Dane:   checkDane(ctx, resolver, mxRecords),
+		Errors: errors,
+	}
+	return report, nil
+}
+
+// findTxtRecord returns the first TXT record of the name that starts with the given version tag.
+func findTxtRecord(ctx context.Context, resolver *Resolver, name string, version string) (*string, error) {
+	records, err := queryRecords(ctx, resolver, name, dns.TypeTXT)
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		if strings.HasPrefix(strings.ToLower(strings.ReplaceAll(record.Value, " ", "")), strings.ToLower(version)) {
+			value := record.Value
+			return &value, nil
+		}
+	}
+	return nil, nil
+}
+
+// checkMtaSts fetches the _mta-sts record and the HTTPS policy it advertises (RFC 8461). The control passes when a
+// valid policy in enforce mode covers every MX host of the domain.
+func checkMtaSts(ctx context.Context, resolver *Resolver, httpClient *http.Client, domain string, mxRecords []*osintscan.DnsRecord) *osintscan.MtaStsPolicy {
+	policy := &osintscan.MtaStsPolicy{
+		Status:    osintscan.EmailControlStatusMissing,
+		PolicyUrl: "https://mta-sts." + domain + "/.well-known/mta-sts.txt",
+	}
+
+	record, err := findTxtRecord(ctx, resolver, "_mta-sts."+domain, "v=STSv1")
+	if err != nil {
+		policy.Findings = append(policy.Findings, err.Error())
+	}
+	if record == nil {
+		return policy
+	}
+	policy.Record = record
+	policy.Status = osintscan.EmailControlStatusFail
+	if id, ok := parseTagList(*record)["id"]; ok && id != "" {
+		policy.Id = &id
+	} else {
+		policy.Findings = append(policy.Findings, "_mta-sts record has no id")
+	}
+
+	// Policy fetches must not follow redirects (RFC 8461 section 3.3)
+	client := *httpClient
+	client.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, policy.PolicyUrl, nil)
+	if err != nil {
+		policy.Findings = append(policy.Findings, err.Error())
+		return policy
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		policy.Findings = append(policy.Findings, fmt.Sprintf("could not fetch policy: %s", err.Error()))
+		return policy
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		policy.Findings = append(policy.Findings, fmt.Sprintf("policy fetch returned HTTP %d", resp.StatusCode))
+		return policy
+	}
+	if !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/plain") {
+		policy.Findings = append(policy.Findings, fmt.Sprintf("policy is served as %q instead of text/plain", resp.Header.Get("Content-Type")))
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, mtaStsPolicyMaxSize))
+	if err != nil {
+		policy.Findings = append(policy.Findings, err.Error())
+		return policy
+	}
+	policyText := string(body)
+	policy.Policy = &policyText
+
+	for _, line := range strings.Split(policyText, "\n") {
+		key, value, found := strings.Cut(line, ":")
+		if !found {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "version":
+			policy.Version = &value
+		case "mode":
+			policy.Mode = &value
+		case "mx":
+			policy.Mx = append(policy.Mx, value)
+		case "max_age":
+			if maxAge, err := strconv.Atoi(value); err == nil {
+				policy.MaxAge = &maxAge
+			} else {
+				policy.Findings = append(policy.Findings, fmt.Sprintf("invalid max_age %q", value))
+			}
+		}
+	}
+
+	valid := true
+	if policy.Version == nil || *policy.Version != "STSv1" {
+		valid = false
+		policy.Findings = append(policy.Findings, "policy version is not STSv1")
+	}
+	switch {
+	case policy.Mode == nil:
+		valid = false
+		policy.Findings = append(policy.Findings, "policy has no mode")
+	case *policy.Mode == "testing":
+		valid = false
+		policy.Findings = append(policy.Findings, "policy is in testing mode and does not block downgraded delivery")
+	case *policy.Mode == "none":
+		valid = false
+		policy.Findings = append(policy.Findings, "policy mode is none")
+	case *policy.Mode != "enforce":
+		valid = false
+		policy.Findings = append(policy.Findings, fmt.Sprintf("unknown policy mode %q", *policy.Mode))
+	}
+	if policy.MaxAge == nil {
+		valid = false
+		policy.Findings = append(policy.Findings, "policy has no max_age")
+	}
+	for _, mxRecord := range mxRecords {
+		if mxRecord.Mx == nil || mxRecord.Mx.Exchange == "" {
+			// A null MX (RFC 7505) accepts no mail, so there is no host for the policy to cover
+			continue
+		}
+		if !slices.ContainsFunc(policy.Mx, func(pattern string) bool { return mtaStsMatches(pattern, mxRecord.Value) }) {
+			valid = false
+			policy.Findings = append(policy.Findings, fmt.Sprintf("MX host %s is not covered by the policy", mxRecord.Value))
+		}
+	}
+
+	if valid {
+		policy.Status = osintscan.EmailControlStatusPass
+	}
+	return policy
+}
+
+// parseTagList splits a tag list into its tag=value pairs. DKIM, DMARC, MTA-STS, TLS-RPT and BIMI records all use the
+// tag list syntax of RFC 6376 section 3.2.
+func parseTagList(record string) map[string]string {
+	tags := map[string]string{}
+	for _, part := range strings.Split(record, ";") {
+		name, value, found := strings.Cut(part, "=")
+		if !found {
+			continue
+		}
+		tags[strings.ToLower(strings.TrimSpace(name))] = strings.TrimSpace(value)
+	}
+	return tags
+}
+
+// mtaStsMatches reports whether the MX host matches a policy mx pattern. A leading wildcard matches exactly one label.
+func mtaStsMatches(pattern string, host string) bool {
+	pattern = strings.TrimSuffix(strings.ToLower(pattern), ".")
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if strings.HasPrefix(pattern, "*.") {
+		label, found := strings.CutSuffix(host, pattern[1:])
+		return found && label != "" && !strings.Contains(label, ".")
+	}
+	return pattern == host
+}
+
+// checkTlsRpt parses the _smtp._tls reporting record (RFC 8460). The control passes when it names at least one valid
+// mailto or https report destination.
+func checkTlsRpt(ctx context.Context, resolver *Resolver, domain string) *osintscan.TlsRptPolicy {
+	policy := &osintscan.TlsRptPolicy{Status: osintscan.EmailControlStatusMissing}
+
+	record, err := findTxtRecord(ctx, resolver, "_smtp._tls."+domain, "v=TLSRPTv1")
+	if err != nil {
+		policy.Findings = append(policy.Findings, err.Error())
+	}
+	if record == nil {
+		return policy
+	}
+	policy.Record = record
+	policy.Status = osintscan.EmailControlStatusFail
+
+	for _, destination := range strings.Split(parseTagList(*record)["rua"], ",") {
+		destination = strings.TrimSpace(destination)
+		if destination == "" {
+			continue
+		}
+		policy.Rua = append(policy.Rua, destination)
+		if parsed, err := url.Parse(destination); err != nil || (parsed.Scheme != "mailto" && parsed.Scheme != "https") {
+			policy.Findings = append(policy.Findings, fmt.Sprintf("unsupported report destination %q", destination))
+		}
+	}
+	if len(policy.Rua) == 0 {
+		policy.Findings = append(policy.Findings, "record has no rua report destination")
+	} else if len(policy.Findings) == 0 {
+		policy.Status = osintscan.EmailControlStatusPass
+	}
+	return policy
+}
+
+// checkBimi parses the default._bimi record and checks that the SVG logo and the verified mark certificate it points to
+// are reachable. The control passes when the logo is an HTTPS URL that can be fetched.
+func checkBimi(ctx context.Context, resolver *Resolver, httpClient *http.Client, domain string) *osintscan.BimiRecord {
+	bimi := &osintscan.BimiRecord{Status: osintscan.EmailControlStatusMissing}
+
+	record, err := findTxtRecord(ctx, resolver, "default._bimi."+domain, "v=BIMI1")
+	if err != nil {
+		bimi.Findings = append(bimi.Findings, err.Error())
+	}
+	if record == nil {
+		return bimi
+	}
+	bimi.Record = record
+	bimi.Status = osintscan.EmailControlStatusFail
+
+	tags := parseTagList(*record)
+	if logo := tags["l"]; logo != "" {
+		bimi.LogoUrl = &logo
+		reachable := checkURLReachable(ctx, httpClient, logo)
+		bimi.LogoReachable = &reachable
+		if !strings.HasPrefix(logo, "https://") {
+			bimi.Findings = append(bimi.Findings, "logo URL must use HTTPS")
+		} else if !reachable {
+			bimi.Findings = append(bimi.Findings, "logo URL could not be fetched")
+		} else {
+			bimi.Status = osintscan.EmailControlStatusPass
+		}
+	} else {
+		bimi.Findings = append(bimi.Findings, "record declines to publish a logo")
+	}
+	if authority := tags["a"]; authority != "" {
+		bimi.AuthorityUrl = &authority
+		reachable := checkURLReachable(ctx, httpClient, authority)
+		bimi.AuthorityReachable = &reachable
+		if !reachable {
+			bimi.Findings = append(bimi.Findings, "verified mark certificate URL could not be fetched")
+		}
+	} else {
+		bimi.Findings = append(bimi.Findings, "no verified mark certificate; most mailbox providers will not display the logo")
+	}
+	return bimi
+}
+
+func checkURLReachable(ctx context.Context, httpClient *http.Client, target string) bool {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return false
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return false
+	}
+	_ = resp.Body.Close()
+	return resp.StatusCode == http.StatusOK
+}
+
+// checkDane looks up the TLSA records of the SMTP service of every MX host (RFC 7672). The control passes when every MX
+// host publishes TLSA records and the resolver validated them with DNSSEC.
+func checkDane(ctx context.Context, resolver *Resolver, mxRecords []*osintscan.DnsRecord) *osintscan.DanePolicy {
+	dane := &osintscan.DanePolicy{Status: osintscan.EmailControlStatusMissing}
+
+	protected := 0
+	for _, mxRecord := range mxRecords {
+		if mxRecord.Mx == nil || mxRecord.Mx.Exchange == "" {
+			// A null MX (RFC 7505) accepts no mail and needs no transport security
+			continue
+		}
+		host := &osintscan.DaneMxHost{
+			Status:     osintscan.EmailControlStatusMissing,
+			Mx:         mxRecord.Mx.Exchange,
+			Preference: mxRecord.Mx.Preference,
+			TlsaName:   "_25._tcp." + mxRecord.Mx.Exchange,
+		}
+
+		tlsaRecords, err := queryRecords(ctx, resolver, host.TlsaName, dns.TypeTLSA)
+		if err != nil {
+			host.Findings = append(host.Findings, err.Error())
+		}
+		host.TlsaRecords = tlsaRecords
+		for _, tlsaRecord := range tlsaRecords {
+			if tlsaRecord.Response != nil && tlsaRecord.Response.AuthenticatedData {
+				host.DnssecValidated = true
+			}
+		}
+
+		switch {
+		case len(tlsaRecords) == 0:
+			host.Findings = append(host.Findings, "no TLSA records published")
+		case !host.DnssecValidated:
+			host.Status = osintscan.EmailControlStatusFail
+			host.Findings = append(host.Findings, "TLSA records were not DNSSEC validated; DANE requires a signed zone")
+		default:
+			host.Status = osintscan.EmailControlStatusPass
+			protected++
+		}
+		dane.MxHosts = append(dane.MxHosts, host)
+	}
+
+	switch {
+	case len(dane.MxHosts) == 0:
+		dane.Findings = append(dane.Findings, "domain has no MX hosts")
+	case protected == len(dane.MxHosts):
+		dane.Status = osintscan.EmailControlStatusPass
+	case protected > 0 || slices.ContainsFunc(dane.MxHosts, func(host *osintscan.DaneMxHost) bool { return len(host.TlsaRecords) > 0 }):
+		dane.Status = osintscan.EmailControlStatusFail
+		dane.Findings = append(dane.Findings, "not every MX host is protected by DANE")
+	}
+	return dane
+}
@@ -0,0 +1,68 @@
+package dns
+
+import (
+	"sort"
+	"strings"
+)
+
+// LearnWordlist learns the naming conventions of the domain from the names in previous subdomain and certificate
+// reports. Every label of the names below the domain is a token, and so is every dash separated part of a label, so
+// that app-eu1-prod.example.com gives app-eu1-prod, app, eu1 and prod. Tokens are ranked by the number of names they
+// appear in, then alphabetically. When exportPath is set the learned tokens are written to it, gzip compressed when it
+// ends in .gz. It returns the learned tokens followed by the entries of the base wordlist that were not learned.
+func LearnWordlist(domain string, reportPaths []string, base []string, exportPath string) ([]string, error) {
+	names, err := ReadSubenumReports(reportPaths)
+	if err != nil {
+		return nil, err
+	}
+	zone := normalizeName(domain)
+
+	counts := map[string]int{}
+	seenNames := map[string]bool{}
+	for _, name := range names {
+		name = normalizeName(strings.TrimPrefix(name, "*."))
+		if seenNames[name] || !isSubdomain(name, zone) {
+			continue
+		}
+		seenNames[name] = true
+
+		// A token counts once per name, however many times it appears in it
+		tokens := map[string]bool{}
+		for _, label := range strings.Split(strings.TrimSuffix(name, "."+zone), ".") {
+			tokens[label] = true
+			for _, part := range strings.Split(label, "-") {
+				if validLabel(part) {
+					tokens[part] = true
+				}
+			}
+		}
+		for token := range tokens {
+			counts[token]++
+		}
+	}
+
+	learned := make([]string, 0, len(counts))
+	for token := range counts {
+		learned = append(learned, token)
+	}
+	sort.Slice(learned, func(i, j int) bool {
+		if counts[learned[i]] != counts[learned[j]] {
+			return counts[learned[i]] > counts[learned[j]]
+		}
+		return learned[i] < learned[j]
+	})
+
+	if exportPath != "" {
+		if err := writeWordlist(exportPath, learned); err != nil {
+			return nil, err
+		}
+	}
+
+	wordlist := learned
+	for _, entry := range base {
+		if _, exists := counts[entry]; !exists {
+			wordlist = append(wordlist, entry)
+		}
+	}
+	return wordlist, nil
+}
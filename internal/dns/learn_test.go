@@ -0,0 +1,100 @@
+package dns
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	osintscan "github.com/Method-Security/osintscan/generated/go"
+)
+
+func TestLearnWordlist(t *testing.T) {
+	tests := []struct {
+		name     string
+		reports  [][]string
+		base     []string
+		wordlist []string
+		exported []string
+	}{
+		{
+			name:     "ranks tokens by the number of names they appear in",
+			reports:  [][]string{{"www.example.com", "api.example.com", "api.dev.example.com", "dev.example.com", "api.eu.example.com"}},
+			wordlist: []string{"api", "dev", "eu", "www"},
+		},
+		{
+			name:     "splits labels on dashes",
+			reports:  [][]string{{"app-eu1-prod.example.com", "prod.example.com"}},
+			wordlist: []string{"prod", "app", "app-eu1-prod", "eu1"},
+		},
+		{
+			name:     "counts a token once per name",
+			reports:  [][]string{{"web.web-web.example.com", "api.example.com", "api.v2.example.com"}},
+			wordlist: []string{"api", "v2", "web", "web-web"},
+		},
+		{
+			name:     "counts a name once across reports",
+			reports:  [][]string{{"api.example.com", "*.api.example.com"}, {"API.Example.com.", "mail.example.com", "mail.eu.example.com"}},
+			wordlist: []string{"mail", "api", "eu"},
+		},
+		{
+			name:     "skips names outside the domain",
+			reports:  [][]string{{"vpn.example.com", "example.com", "www.example.org", "www.notexample.com"}},
+			wordlist: []string{"vpn"},
+		},
+		{
+			name:     "keeps labels whose parts are not valid",
+			reports:  [][]string{{"a--b.example.com"}},
+			wordlist: []string{"a", "a--b", "b"},
+		},
+		{
+			name:     "appends the base entries that were not learned",
+			reports:  [][]string{{"api.example.com", "ftp.example.com", "ftp.eu.example.com"}},
+			base:     []string{"www", "api", "mail"},
+			wordlist: []string{"ftp", "api", "eu", "www", "mail"},
+			exported: []string{"ftp", "api", "eu"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dir := t.TempDir()
+			var paths []string
+			for i, names := range test.reports {
+				data, err := json.Marshal(osintscan.DnsSubenumReport{Domain: "example.com", Subdomains: names})
+				if err != nil {
+					t.Fatal(err)
+				}
+				path := filepath.Join(dir, fmt.Sprintf("report%d.json", i))
+				if err := os.WriteFile(path, data, 0o600); err != nil {
+					t.Fatal(err)
+				}
+				paths = append(paths, path)
+			}
+
+			exportPath := filepath.Join(dir, "learned.txt")
+			wordlist, err := LearnWordlist("Example.com", paths, test.base, exportPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(wordlist, test.wordlist) {
+				t.Errorf("got wordlist %v, want %v", wordlist, test.wordlist)
+			}
+
+			// Only the learned tokens are exported, without the base entries
+			exported, err := os.ReadFile(exportPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := test.exported
+			if want == nil {
+				want = test.wordlist
+			}
+			if got := strings.Fields(string(exported)); !slices.Equal(got, want) {
+				t.Errorf("got exported tokens %v, want %v", got, want)
+			}
+		})
+	}
+}
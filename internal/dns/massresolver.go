@@ -0,0 +1,595 @@
+package dns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+const (
+	// massUDPSize is the EDNS buffer size advertised by the mass resolver. It is the DNS flag day 2020 recommendation,
+	// large enough for address answers without risking IP fragmentation.
+	massUDPSize = 1232
+	// massSocketQueries is the number of queries kept in flight on a single UDP socket. Every 512 queries in flight get
+	// a socket of their own, which spreads the answers over more source ports and receive buffers.
+	massSocketQueries = 512
+)
+
+// MassResolverConfig contains the configuration used to build a MassResolver.
+type MassResolverConfig struct {
+	// InFlight is the number of names being resolved at once, each with a single query in flight
+	InFlight int
+	// RateLimit is the number of queries per second sent to each resolver, or 0 for no limit
+	RateLimit int
+}
+
+// MassResult is the outcome of resolving a single name with a MassResolver.
+type MassResult struct {
+	// Index is the position of the name in the order the names were emitted
+	Index     int
+	Name      string
+	Addresses []string
+	// Cnames is the CNAME chain of the name, in order, without trailing dots
+	Cnames []string
+	Rcode  int
+	Err    error
+}
+
+// MassResolver resolves large batches of names against the servers of a Resolver. Like massdns, it is an asynchronous
+// engine: a single event loop sends the queries of many names at once and matches the answers to them as they arrive,
+// so the number of queries in flight is not bound to a number of goroutines or sockets. Plain UDP servers are queried
+// over a few raw sockets, each split into a sender and a receiver, while DNS-over-TCP, TLS and HTTPS servers are queried
+// through their regular client. Queries that time out or are answered with SERVFAIL or REFUSED are retried against the
+// next server in the pool.
+type MassResolver struct {
+	servers  []*massServer
+	inFlight int
+	timeout  time.Duration
+	retries  int
+	next     uint32
+}
+
+// massJob is a name waiting in the mass resolver's queue.
+type massJob struct {
+	index int
+	name  string
+}
+
+// massServer is an upstream of the mass resolver along with the state of its rate limit. Plain UDP upstreams also hold
+// their parsed address, so that they can be queried over the mass resolver's sockets.
+type massServer struct {
+	upstream upstream
+	udp      *dnsUpstream
+	address  *net.UDPAddr
+	interval time.Duration
+	mutex    sync.Mutex
+	nextSlot time.Time
+}
+
+// massLookup is the resolution of a single name. It asks for A records, then AAAA records when the name has no IPv4
+// address, and chases the CNAME chain of the name when it cannot be resolved.
+type massLookup struct {
+	result MassResult
+	// question is the type of the question currently being asked
+	question uint16
+	// current is the name whose CNAME is being chased
+	current string
+}
+
+// massQuery is the question a lookup is currently asking, along with the server it is sent to.
+type massQuery struct {
+	lookup  *massLookup
+	msg     *dns.Msg
+	start   int
+	attempt int
+	server  int
+}
+
+// massKey identifies a query waiting for its answer on a socket.
+type massKey struct {
+	id       uint16
+	name     string
+	question uint16
+}
+
+// massSocket is a UDP socket of the mass resolver. Its sender writes the queued datagrams and its receiver parses the
+// datagrams that arrive, while the queries waiting for an answer are only touched by the event loop.
+type massSocket struct {
+	conn     *net.UDPConn
+	outgoing chan massDatagram
+	pending  map[massKey]*massQuery
+}
+
+// massDatagram is a packed query waiting to be written by a socket's sender.
+type massDatagram struct {
+	packed  []byte
+	address *net.UDPAddr
+}
+
+// massDeadline is the time at which a query sent on a socket times out.
+type massDeadline struct {
+	socket   *massSocket
+	key      massKey
+	query    *massQuery
+	deadline time.Time
+}
+
+// massReply is a message received by the event loop. Datagrams received on a socket are matched to their query by the
+// loop, while answers from other transports already carry the query they answer.
+type massReply struct {
+	socket *massSocket
+	from   *net.UDPAddr
+	query  *massQuery
+	msg    *dns.Msg
+	err    error
+}
+
+// massRun is the state of the event loop of a single Resolve call.
+type massRun struct {
+	m       *MassResolver
+	ctx     context.Context
+	handle  func(result MassResult)
+	sockets []*massSocket
+	// nextSocket is the socket the next plain UDP query is sent on
+	nextSocket int
+	replies    chan massReply
+	done       chan struct{}
+	active     map[*massLookup]struct{}
+	// queues hold the queries waiting for the rate limit of each server, and waiting lists the servers with queued queries
+	queues  [][]*massQuery
+	waiting []int
+	// deadlines are ordered by time, since every query sent on a socket times out after the same duration
+	deadlines []massDeadline
+}
+
+// NewMassResolver creates a MassResolver from the servers of the resolver.
+func NewMassResolver(resolver *Resolver, config MassResolverConfig) (*MassResolver, error) {
+	inFlight := config.InFlight
+	if inFlight <= 0 {
+		inFlight = 1
+	}
+	var interval time.Duration
+	if config.RateLimit > 0 {
+		interval = time.Second / time.Duration(config.RateLimit)
+	}
+
+	m := &MassResolver{inFlight: inFlight, timeout: resolver.timeout, retries: resolver.retries}
+	for _, upstream := range resolver.upstreams {
+		server := &massServer{upstream: upstream, interval: interval}
+		if udp, ok := upstream.(*dnsUpstream); ok && udp.network == "udp" {
+			address, err := net.ResolveUDPAddr("udp", udp.address)
+			if err != nil {
+				return nil, fmt.Errorf("invalid resolver %q: %w", udp.address, err)
+			}
+			server.udp = udp
+			server.address = address
+		}
+		m.servers = append(m.servers, server)
+	}
+	return m, nil
+}
+
+// Resolve looks up the addresses of every name emitted by names. Names are produced lazily into a bounded queue and
+// taken from it whenever fewer names than the in-flight limit are being resolved, so memory use does not grow with the
+// number of names. The handle function is called with each result from the calling goroutine, so it needs no locking.
+// Resolve returns once every name has been handled or the context is done.
+func (m *MassResolver) Resolve(ctx context.Context, names func(emit func(name string) bool), handle func(result MassResult)) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	jobs := make(chan massJob, m.inFlight)
+	go func() {
+		defer close(jobs)
+		index := 0
+		names(func(name string) bool {
+			select {
+			case jobs <- massJob{index: index, name: name}:
+				index++
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		})
+	}()
+
+	run := &massRun{
+		m:       m,
+		ctx:     ctx,
+		handle:  handle,
+		replies: make(chan massReply, m.inFlight),
+		done:    make(chan struct{}),
+		active:  make(map[*massLookup]struct{}),
+		queues:  make([][]*massQuery, len(m.servers)),
+	}
+	if err := run.open(); err != nil {
+		for job := range jobs {
+			handle(MassResult{Index: job.index, Name: job.name, Err: err})
+		}
+		return
+	}
+	defer run.close()
+	run.loop(jobs)
+}
+
+// open creates the sockets of the run when any server is queried over plain UDP, and starts their senders and
+// receivers.
+func (r *massRun) open() error {
+	udp := false
+	for _, server := range r.m.servers {
+		udp = udp || server.udp != nil
+	}
+	if !udp {
+		return nil
+	}
+	for i := 0; i < (r.m.inFlight+massSocketQueries-1)/massSocketQueries; i++ {
+		conn, err := net.ListenUDP("udp", nil)
+		if err != nil {
+			r.close()
+			return err
+		}
+		socket := &massSocket{
+			conn:     conn,
+			outgoing: make(chan massDatagram, massSocketQueries),
+			pending:  make(map[massKey]*massQuery),
+		}
+		r.sockets = append(r.sockets, socket)
+		go socket.send()
+		go socket.receive(r.replies, r.done)
+	}
+	return nil
+}
+
+// close stops the senders and receivers of the run's sockets, along with any exchange still running over another
+// transport.
+func (r *massRun) close() {
+	close(r.done)
+	for _, socket := range r.sockets {
+		close(socket.outgoing)
+		_ = socket.conn.Close()
+	}
+}
+
+// loop takes names from the queue and resolves them until the queue is drained and every name has been handled. Every
+// pass sends the queries the rate limits allow and times out the queries whose deadline has passed, then waits for a
+// new name, an answer, or the next deadline or rate limit slot.
+func (r *massRun) loop(jobs <-chan massJob) {
+	timer := time.NewTimer(time.Hour)
+	defer timer.Stop()
+	for jobs != nil || len(r.active) > 0 {
+		now := time.Now()
+		r.expire(now)
+		wake := r.flush(now)
+		if len(r.deadlines) > 0 && (wake.IsZero() || r.deadlines[0].deadline.Before(wake)) {
+			wake = r.deadlines[0].deadline
+		}
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		if wake.IsZero() {
+			timer.Reset(time.Hour)
+		} else {
+			timer.Reset(time.Until(wake))
+		}
+
+		var admit <-chan massJob
+		if len(r.active) < r.m.inFlight {
+			admit = jobs
+		}
+		select {
+		case job, ok := <-admit:
+			if !ok {
+				jobs = nil
+				continue
+			}
+			lookup := &massLookup{result: MassResult{Index: job.index, Name: job.name}}
+			r.active[lookup] = struct{}{}
+			r.ask(lookup, job.name, dns.TypeA)
+		case reply := <-r.replies:
+			r.receive(reply)
+		case <-timer.C:
+		case <-r.ctx.Done():
+			r.abort()
+			return
+		}
+	}
+}
+
+// abort hands every name still being resolved to the handler, in the order the names were emitted, with the error of
+// the context.
+func (r *massRun) abort() {
+	var lookups []*massLookup
+	for lookup := range r.active {
+		lookups = append(lookups, lookup)
+	}
+	sort.Slice(lookups, func(i, j int) bool { return lookups[i].result.Index < lookups[j].result.Index })
+	for _, lookup := range lookups {
+		lookup.result.Err = r.ctx.Err()
+		r.handle(lookup.result)
+	}
+}
+
+// ask queues a question for the lookup, starting at the next server in the pool.
+func (r *massRun) ask(lookup *massLookup, name string, questionType uint16) {
+	lookup.question = questionType
+	msg := &dns.Msg{}
+	msg.SetQuestion(dns.Fqdn(name), questionType)
+	msg.SetEdns0(massUDPSize, false)
+	r.enqueue(&massQuery{lookup: lookup, msg: msg, start: int(atomic.AddUint32(&r.m.next, 1))})
+}
+
+// enqueue queues the query for the server of its attempt.
+func (r *massRun) enqueue(query *massQuery) {
+	query.server = (query.start + query.attempt) % len(r.m.servers)
+	if len(r.queues[query.server]) == 0 {
+		r.waiting = append(r.waiting, query.server)
+	}
+	r.queues[query.server] = append(r.queues[query.server], query)
+}
+
+// flush sends the queued queries of every server until its rate limit is reached. It returns the earliest time at
+// which a server with queries left can be sent another one, or the zero time when no queries are left.
+func (r *massRun) flush(now time.Time) time.Time {
+	var wake time.Time
+	waiting := r.waiting[:0]
+	for _, index := range r.waiting {
+		server := r.m.servers[index]
+		queue := r.queues[index]
+		for len(queue) > 0 {
+			slot, ok := server.take(now)
+			if !ok {
+				if wake.IsZero() || slot.Before(wake) {
+					wake = slot
+				}
+				break
+			}
+			r.send(queue[0], now)
+			queue[0] = nil
+			queue = queue[1:]
+		}
+		r.queues[index] = queue
+		if len(queue) > 0 {
+			waiting = append(waiting, index)
+		}
+	}
+	r.waiting = waiting
+	return wake
+}
+
+// send sends the query to its server. Plain UDP queries are given an ID that no other query with the same question
+// waits on in the socket, while queries to other transports are exchanged in a goroutine of their own.
+func (r *massRun) send(query *massQuery, now time.Time) {
+	server := r.m.servers[query.server]
+	if server.udp == nil {
+		query.msg.Id = dns.Id()
+		msg := query.msg.Copy()
+		go func() {
+			resp, _, err := server.upstream.exchange(r.ctx, msg)
+			r.post(massReply{query: query, msg: resp, err: err})
+		}()
+		return
+	}
+
+	socket := r.sockets[r.nextSocket]
+	r.nextSocket = (r.nextSocket + 1) % len(r.sockets)
+	key := massKey{name: strings.ToLower(query.msg.Question[0].Name), question: query.msg.Question[0].Qtype}
+	for {
+		key.id = dns.Id()
+		if _, taken := socket.pending[key]; !taken {
+			break
+		}
+	}
+	query.msg.Id = key.id
+	packed, err := query.msg.Pack()
+	if err != nil {
+		r.answer(query, nil, err)
+		return
+	}
+	socket.pending[key] = query
+	r.deadlines = append(r.deadlines, massDeadline{socket: socket, key: key, query: query, deadline: now.Add(r.m.timeout)})
+	socket.outgoing <- massDatagram{packed: packed, address: server.address}
+}
+
+// expire times out the queries whose deadline has passed and that are still waiting for their answer.
+func (r *massRun) expire(now time.Time) {
+	for len(r.deadlines) > 0 && !r.deadlines[0].deadline.After(now) {
+		expired := r.deadlines[0]
+		r.deadlines = r.deadlines[1:]
+		if expired.socket.pending[expired.key] != expired.query {
+			continue
+		}
+		delete(expired.socket.pending, expired.key)
+		r.answer(expired.query, nil, errors.New("timed out waiting for an answer"))
+	}
+}
+
+// receive hands a reply to the query it answers. Datagrams that do not answer a query waiting on the socket, such as
+// late answers to an attempt that timed out or answers from another address, are discarded. Truncated answers from
+// plain UDP servers are repeated over TCP against the same server.
+func (r *massRun) receive(reply massReply) {
+	query := reply.query
+	if query == nil {
+		if len(reply.msg.Question) != 1 {
+			return
+		}
+		question := reply.msg.Question[0]
+		key := massKey{id: reply.msg.Id, name: strings.ToLower(question.Name), question: question.Qtype}
+		query = reply.socket.pending[key]
+		if query == nil {
+			return
+		}
+		server := r.m.servers[query.server]
+		if !reply.from.IP.Equal(server.address.IP) || reply.from.Port != server.address.Port {
+			return
+		}
+		delete(reply.socket.pending, key)
+		if reply.msg.Truncated {
+			msg := query.msg.Copy()
+			go func() {
+				resp, _, err := server.udp.fallback.ExchangeContext(r.ctx, msg, server.udp.address)
+				r.post(massReply{query: query, msg: resp, err: err})
+			}()
+			return
+		}
+	}
+	r.answer(query, reply.msg, reply.err)
+}
+
+// post hands a reply to the event loop, unless the run is over.
+func (r *massRun) post(reply massReply) {
+	select {
+	case r.replies <- reply:
+	case <-r.done:
+	}
+}
+
+// answer handles the outcome of a query, rotating to the next server whenever the current one failed or answered with
+// SERVFAIL or REFUSED.
+func (r *massRun) answer(query *massQuery, resp *dns.Msg, err error) {
+	server := r.m.servers[query.server]
+	switch {
+	case err != nil:
+		err = fmt.Errorf("%s: %w", server.upstream, err)
+	case resp.Rcode == dns.RcodeServerFailure || resp.Rcode == dns.RcodeRefused:
+		err = fmt.Errorf("%s: %s query for %s returned %s", server.upstream, dns.TypeToString[query.msg.Question[0].Qtype], query.msg.Question[0].Name, dns.RcodeToString[resp.Rcode])
+	}
+	if err != nil {
+		query.attempt++
+		if query.attempt < r.m.retries {
+			r.enqueue(query)
+			return
+		}
+		resp = nil
+	}
+	r.advance(query.lookup, resp, err)
+}
+
+// advance moves the lookup on after the answer to its current question. The IPv4 addresses of the name are kept, or
+// its IPv6 addresses when it has no IPv4 address. When the name cannot be resolved its CNAME chain is followed one hop
+// at a time, so that names pointing at a broken target still report their chain.
+func (r *massRun) advance(lookup *massLookup, resp *dns.Msg, err error) {
+	result := &lookup.result
+	if lookup.question == dns.TypeCNAME {
+		if err != nil {
+			r.finish(lookup)
+			return
+		}
+		hop := findCNAME(resp.Answer, lookup.current)
+		if hop == nil {
+			r.finish(lookup)
+			return
+		}
+		lookup.current = dns.CanonicalName(hop.Target)
+		result.Cnames = append(result.Cnames, strings.TrimSuffix(lookup.current, "."))
+		if len(result.Cnames) >= traceMaxCNAMEs {
+			r.finish(lookup)
+			return
+		}
+		r.ask(lookup, lookup.current, dns.TypeCNAME)
+		return
+	}
+
+	if err != nil {
+		result.Err = err
+		result.Cnames = nil
+		lookup.current = dns.CanonicalName(result.Name)
+		r.ask(lookup, lookup.current, dns.TypeCNAME)
+		return
+	}
+	result.Rcode = resp.Rcode
+	result.Cnames = cnameChain(resp.Answer, result.Name)
+	for _, rr := range resp.Answer {
+		switch record := rr.(type) {
+		case *dns.A:
+			result.Addresses = append(result.Addresses, record.A.String())
+		case *dns.AAAA:
+			result.Addresses = append(result.Addresses, record.AAAA.String())
+		}
+	}
+	if lookup.question == dns.TypeA && len(result.Addresses) == 0 && resp.Rcode == dns.RcodeSuccess {
+		r.ask(lookup, result.Name, dns.TypeAAAA)
+		return
+	}
+	r.finish(lookup)
+}
+
+// finish hands the result of the lookup to the handler.
+func (r *massRun) finish(lookup *massLookup) {
+	delete(r.active, lookup)
+	r.handle(lookup.result)
+}
+
+// send writes the queued datagrams to the socket until it is closed. A datagram that cannot be written is sent again
+// when its query times out.
+func (s *massSocket) send() {
+	for datagram := range s.outgoing {
+		_, _ = s.conn.WriteToUDP(datagram.packed, datagram.address)
+	}
+}
+
+// receive parses the datagrams arriving on the socket and hands them to the event loop until the socket is closed.
+func (s *massSocket) receive(replies chan<- massReply, done <-chan struct{}) {
+	buffer := make([]byte, dns.MaxMsgSize)
+	for {
+		n, from, err := s.conn.ReadFromUDP(buffer)
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return
+			}
+			continue
+		}
+		msg := &dns.Msg{}
+		if err := msg.Unpack(buffer[:n]); err != nil {
+			continue
+		}
+		select {
+		case replies <- massReply{socket: s, from: from, msg: msg}:
+		case <-done:
+			return
+		}
+	}
+}
+
+// cnameChain returns the CNAME chain of the name found in the answer section.
+func cnameChain(answer []dns.RR, name string) []string {
+	var chain []string
+	current := dns.CanonicalName(name)
+	for len(chain) < traceMaxCNAMEs {
+		hop := findCNAME(answer, current)
+		if hop == nil {
+			break
+		}
+		current = dns.CanonicalName(hop.Target)
+		chain = append(chain, strings.TrimSuffix(current, "."))
+	}
+	return chain
+}
+
+// take claims the next slot of the server's rate limit when it has passed. Otherwise it returns the time of the next
+// slot. A slot missed by less than an interval can still be claimed, so that a late wake-up does not lower the rate.
+func (s *massServer) take(now time.Time) (time.Time, bool) {
+	if s.interval == 0 {
+		return now, true
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.nextSlot.After(now) {
+		return s.nextSlot, false
+	}
+	slot := s.nextSlot
+	if slot.Before(now.Add(-s.interval)) {
+		slot = now
+	}
+	s.nextSlot = slot.Add(s.interval)
+	return slot, true
+}
@@ -0,0 +1,280 @@
+package dns
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// answerA returns an answer to the request holding a single A record with the address.
+func answerA(req *dns.Msg, address string) *dns.Msg {
+	resp := &dns.Msg{}
+	resp.SetReply(req)
+	resp.Answer = append(resp.Answer, &dns.A{
+		Hdr: dns.RR_Header{Name: req.Question[0].Name, Rrtype: dns.TypeA, Class: dns.ClassINET, Ttl: 60},
+		A:   net.ParseIP(address),
+	})
+	return resp
+}
+
+// resolveAll resolves the names with the engine and returns the results in the order the names were emitted.
+func resolveAll(engine *MassResolver, names ...string) []MassResult {
+	results := make([]MassResult, len(names))
+	engine.Resolve(context.Background(), func(emit func(string) bool) {
+		for _, name := range names {
+			if !emit(name) {
+				return
+			}
+		}
+	}, func(result MassResult) {
+		results[result.Index] = result
+	})
+	return results
+}
+
+func TestMassResolverExchange(t *testing.T) {
+	stray, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = stray.Close() })
+
+	tests := []struct {
+		name    string
+		handler func(w dns.ResponseWriter, req *dns.Msg, attempt int)
+		timeout time.Duration
+		retries int
+		address string
+	}{
+		{
+			name: "discards answers with another ID",
+			handler: func(w dns.ResponseWriter, req *dns.Msg, attempt int) {
+				wrong := answerA(req, "192.0.2.66")
+				wrong.Id++
+				_ = w.WriteMsg(wrong)
+				_ = w.WriteMsg(answerA(req, "192.0.2.1"))
+			},
+			address: "192.0.2.1",
+		},
+		{
+			name: "discards answers to another question",
+			handler: func(w dns.ResponseWriter, req *dns.Msg, attempt int) {
+				wrong := answerA(req, "192.0.2.66")
+				wrong.Question[0].Name = "other.example.com."
+				_ = w.WriteMsg(wrong)
+				_ = w.WriteMsg(answerA(req, "192.0.2.1"))
+			},
+			address: "192.0.2.1",
+		},
+		{
+			name: "discards answers from another address",
+			handler: func(w dns.ResponseWriter, req *dns.Msg, attempt int) {
+				packed, _ := answerA(req, "192.0.2.66").Pack()
+				_, _ = stray.WriteTo(packed, w.RemoteAddr())
+				time.Sleep(50 * time.Millisecond)
+				_ = w.WriteMsg(answerA(req, "192.0.2.1"))
+			},
+			address: "192.0.2.1",
+		},
+		{
+			name: "discards late answers to an attempt that timed out",
+			handler: func(w dns.ResponseWriter, req *dns.Msg, attempt int) {
+				// The first answer arrives while the retry is waiting for its own answer
+				if attempt == 1 {
+					time.Sleep(600 * time.Millisecond)
+					_ = w.WriteMsg(answerA(req, "192.0.2.66"))
+					return
+				}
+				time.Sleep(300 * time.Millisecond)
+				_ = w.WriteMsg(answerA(req, "192.0.2.1"))
+			},
+			timeout: 400 * time.Millisecond,
+			retries: 2,
+			address: "192.0.2.1",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var mutex sync.Mutex
+			attempts := 0
+			address := serveDNS(t, dns.HandlerFunc(func(w dns.ResponseWriter, req *dns.Msg) {
+				if req.Question[0].Qtype != dns.TypeA {
+					resp := &dns.Msg{}
+					_ = w.WriteMsg(resp.SetReply(req))
+					return
+				}
+				mutex.Lock()
+				attempts++
+				attempt := attempts
+				mutex.Unlock()
+				test.handler(w, req, attempt)
+			}))
+			timeout := test.timeout
+			if timeout == 0 {
+				timeout = time.Second
+			}
+			retries := max(test.retries, 1)
+			resolver, err := NewResolver(ResolverConfig{Servers: []string{address}, Timeout: timeout, Retries: retries})
+			if err != nil {
+				t.Fatal(err)
+			}
+			engine, err := NewMassResolver(resolver, MassResolverConfig{InFlight: 1})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			result := resolveAll(engine, "www.example.com")[0]
+			if result.Err != nil {
+				t.Fatal(result.Err)
+			}
+			if !slices.Equal(result.Addresses, []string{test.address}) {
+				t.Errorf("got addresses %v, want %s", result.Addresses, test.address)
+			}
+		})
+	}
+}
+
+func TestMassResolverRetries(t *testing.T) {
+	tests := []struct {
+		name      string
+		modes     []string
+		retries   int
+		addresses []string
+		queries   int32
+		wantErr   bool
+	}{
+		{name: "falls back to TCP when truncated", modes: []string{"truncate"}, retries: 1, addresses: []string{"192.0.2.1"}, queries: 2},
+		{name: "rotates past SERVFAIL", modes: []string{"servfail", "answer"}, retries: 2, addresses: []string{"192.0.2.1"}, queries: 2},
+		{name: "fails when every server refuses", modes: []string{"refused", "servfail"}, retries: 2, queries: 2, wantErr: true},
+		{name: "does not retry NXDOMAIN", modes: []string{"nxdomain", "answer"}, retries: 2, queries: 1},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var servers []*testServer
+			var addresses []string
+			for _, mode := range test.modes {
+				server := startTestServer(t, mode)
+				servers = append(servers, server)
+				addresses = append(addresses, server.address)
+			}
+			resolver, err := NewResolver(ResolverConfig{Servers: addresses, Timeout: time.Second, Retries: test.retries})
+			if err != nil {
+				t.Fatal(err)
+			}
+			engine, err := NewMassResolver(resolver, MassResolverConfig{InFlight: 1})
+			if err != nil {
+				t.Fatal(err)
+			}
+			// Every name starts at the next server, so start the pool at the last server to make the order predictable
+			engine.next = uint32(len(addresses) - 1)
+
+			// Failed names also have their CNAME chain chased, so queries are only counted for names that resolve
+			result := resolveAll(engine, "www.example.com")[0]
+			var queries int32
+			for _, server := range servers {
+				queries += server.queries.Load()
+			}
+			if test.wantErr {
+				if result.Err == nil {
+					t.Errorf("got addresses %v, want an error", result.Addresses)
+				}
+				return
+			}
+			if queries != test.queries {
+				t.Errorf("sent %d queries, want %d", queries, test.queries)
+			}
+			if result.Err != nil {
+				t.Fatal(result.Err)
+			}
+			if !slices.Equal(result.Addresses, test.addresses) {
+				t.Errorf("got addresses %v, want %v", result.Addresses, test.addresses)
+			}
+		})
+	}
+}
+
+func TestMassResolverInFlight(t *testing.T) {
+	// Every answer takes a while, so resolving the names one after the other would take far longer than the timeout
+	address := serveDNS(t, dns.HandlerFunc(func(w dns.ResponseWriter, req *dns.Msg) {
+		time.Sleep(200 * time.Millisecond)
+		_ = w.WriteMsg(answerA(req, "192.0.2.1"))
+	}))
+	resolver, err := NewResolver(ResolverConfig{Servers: []string{address}, Timeout: 2 * time.Second, Retries: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	engine, err := NewMassResolver(resolver, MassResolverConfig{InFlight: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for i := 0; i < 100; i++ {
+		names = append(names, fmt.Sprintf("host%d.example.com", i))
+	}
+	start := time.Now()
+	results := resolveAll(engine, names...)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("resolving %d names took %s, want them resolved at once", len(names), elapsed)
+	}
+	for _, result := range results {
+		if result.Err != nil || len(result.Addresses) != 1 {
+			t.Errorf("got addresses %v and error %v for %s, want one address", result.Addresses, result.Err, result.Name)
+		}
+	}
+}
+
+func TestMassResolverRateLimit(t *testing.T) {
+	server := startTestServer(t, "answer")
+	resolver, err := NewResolver(ResolverConfig{Servers: []string{server.address}, Timeout: time.Second, Retries: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	engine, err := NewMassResolver(resolver, MassResolverConfig{InFlight: 100, RateLimit: 20})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	resolveAll(engine, "a.example.com", "b.example.com", "c.example.com", "d.example.com", "e.example.com", "f.example.com", "g.example.com", "h.example.com", "i.example.com", "j.example.com")
+	// Ten queries at twenty per second leave nine intervals of 50ms between them
+	if elapsed := time.Since(start); elapsed < 400*time.Millisecond {
+		t.Errorf("sent 10 queries in %s, want at least 450ms at 20 queries per second", elapsed)
+	}
+}
+
+func TestMassResolverCanceled(t *testing.T) {
+	address := serveDNS(t, dns.HandlerFunc(func(w dns.ResponseWriter, req *dns.Msg) {}))
+	resolver, err := NewResolver(ResolverConfig{Servers: []string{address}, Timeout: 10 * time.Second, Retries: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	engine, err := NewMassResolver(resolver, MassResolverConfig{InFlight: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	var results []MassResult
+	engine.Resolve(ctx, func(emit func(string) bool) {
+		for i := 0; emit(fmt.Sprintf("host%d.example.com", i)); i++ {
+		}
+	}, func(result MassResult) {
+		results = append(results, result)
+	})
+	if len(results) != 10 {
+		t.Fatalf("got %d results, want the 10 names in flight", len(results))
+	}
+	for i, result := range results {
+		if result.Index != i || result.Err != context.DeadlineExceeded {
+			t.Errorf("got result %d for name %d with error %v, want the names in order with the context error", i, result.Index, result.Err)
+		}
+	}
+}
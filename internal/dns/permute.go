@@ -0,0 +1,326 @@
+package dns
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	osintscan "github.com/Method-Security/osintscan/generated/go"
+)
+
+// environmentWords are labels that name a deployment environment. A label, or a dash separated part of one, that is an
+// environment word is swapped for every other environment word.
+var environmentWords = []string{
+	"dev", "development", "devel",
+	"stg", "stage", "staging",
+	"prod", "production", "prd",
+	"test", "testing", "tst", "qa", "uat",
+	"preprod", "pre",
+	"sandbox", "sbx",
+	"demo",
+}
+
+// GetDomainSubdomainsPermute generates altdns and dnsgen style variants of known subdomains of the domain and resolves
+// them. Variants number labels up and down, swap environment words, and insert, replace and append the tokens with "."
+// and "-" joiners. At most maxCandidates variants are generated. Variants are resolved by a MassResolver with
+// parallelThreads queries in flight and at most rateLimit queries per second sent to each resolver, after detecting
+// the wildcards of their parent domains. It returns a DnsSubenumReport struct containing the variants that exist and
+// any errors that occurred.
+func GetDomainSubdomainsPermute(ctx context.Context, domain string, known []string, tokens []string, parallelThreads int, rateLimit int, maxCandidates int, timeout int, fingerprintsPath string, resolver *Resolver) (osintscan.DnsSubenumReport, error) {
+	report := osintscan.DnsSubenumReport{
+		Domain:          domain,
+		EnumerationType: osintscan.DnsSubenumTypePermute,
+		Subdomains:      []string{},
+	}
+	errors := []string{}
+	zone := normalizeName(domain)
+
+	var names []string
+	outside := 0
+	seen := map[string]bool{}
+	for _, name := range known {
+		name = normalizeName(name)
+		switch {
+		case name == "" || seen[name]:
+			continue
+		case name == zone || !strings.HasSuffix(name, "."+zone):
+			outside++
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	if outside > 0 {
+		errors = append(errors, fmt.Sprintf("skipped %d names that are not subdomains of %s", outside, zone))
+	}
+	if len(names) == 0 {
+		return report, fmt.Errorf("no known subdomains of %s to permute", zone)
+	}
+
+	engine, err := NewMassResolver(resolver, MassResolverConfig{InFlight: parallelThreads, RateLimit: rateLimit})
+	if err != nil {
+		return report, err
+	}
+	fingerprints, err := retrieveFingerprints(fingerprintsPath)
+	if err != nil {
+		errors = append(errors, fmt.Sprintf("dangling CNAMEs will not be matched to services: %s", err.Error()))
+	}
+
+	var cancel context.CancelFunc
+	if timeout != 0 {
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(timeout)*time.Minute)
+		defer cancel()
+	}
+
+	// The known subdomains are the input, so only new names are reported
+	reported := reportedNames(&report)
+	for _, name := range names {
+		reported[name] = -1
+	}
+	errors = append(errors, permuteSubdomains(ctx, &report, reported, names, zone, tokens, maxCandidates, engine, fingerprints)...)
+	errors = append(errors, separateEmptyNonTerminals(ctx, &report, resolver)...)
+
+	report.Errors = errors
+	return report, nil
+}
+
+// permuteSubdomains generates the variants of the names, which are subdomains of the zone, and resolves them after
+// detecting the wildcards of their parent domains. The variants that exist and are not in reported yet are recorded in
+// the report with the permute method. It returns any non-fatal errors that occurred.
+func permuteSubdomains(ctx context.Context, report *osintscan.DnsSubenumReport, reported map[string]int, names []string, zone string, tokens []string, maxCandidates int, engine *MassResolver, fingerprints []osintscan.Fingerprint) []string {
+	errors := []string{}
+
+	var cleanTokens []string
+	seenTokens := map[string]bool{}
+	for _, token := range tokens {
+		token = strings.ToLower(strings.TrimSpace(token))
+		if validLabel(token) && !seenTokens[token] {
+			seenTokens[token] = true
+			cleanTokens = append(cleanTokens, token)
+		}
+	}
+
+	candidates, truncated := generateVariants(names, zone, cleanTokens, maxCandidates)
+	if truncated {
+		errors = append(errors, fmt.Sprintf("generated more than %d variants; only the first %d are resolved", maxCandidates, maxCandidates))
+	}
+
+	var parents []string
+	seenParents := map[string]bool{}
+	for _, candidate := range candidates {
+		if _, parent, _ := strings.Cut(candidate.name, "."); !seenParents[parent] {
+			seenParents[parent] = true
+			parents = append(parents, parent)
+		}
+	}
+	wildcards, dangling := detectWildcards(ctx, parents, engine, fingerprints)
+	report.Wildcards = append(report.Wildcards, wildcards...)
+	report.DanglingCnames = append(report.DanglingCnames, dangling...)
+
+	byDomain := wildcardsByDomain(wildcards)
+	emit := func(emit func(string) bool) {
+		for _, candidate := range candidates {
+			if !emit(candidate.name) {
+				return
+			}
+		}
+	}
+	engine.Resolve(ctx, emit, func(result MassResult) {
+		recordResult(report, reported, result, byDomain, fingerprints, osintscan.SubdomainMethodPermute, candidates[result.Index].token)
+	})
+	if ctx.Err() != nil {
+		errors = append(errors, fmt.Sprintf("enumeration stopped before every variant was resolved: %s", ctx.Err()))
+	}
+	return errors
+}
+
+// variant is a name generated from a known subdomain, along with the token that was put into it. The token of a number
+// variant is the renumbered label, and the token of an environment variant is the environment word.
+type variant struct {
+	name  string
+	token string
+}
+
+// generateVariants returns the variants of the names, without the names themselves, in the order they were generated.
+// Number and environment variants come first since they are the most likely to exist. It also reports whether more
+// than limit variants could have been generated.
+func generateVariants(names []string, zone string, tokens []string, limit int) ([]variant, bool) {
+	var variants []variant
+	seen := map[string]bool{}
+	for _, name := range names {
+		seen[name] = true
+	}
+	full := false
+	add := func(token string, labels []string) {
+		if full {
+			return
+		}
+		for _, label := range labels {
+			if !validLabel(label) {
+				return
+			}
+		}
+		name := strings.Join(labels, ".") + "." + zone
+		if len(name) > 253 || seen[name] {
+			return
+		}
+		if len(variants) >= limit {
+			full = true
+			return
+		}
+		seen[name] = true
+		variants = append(variants, variant{name: name, token: token})
+	}
+	// replace returns a copy of the labels with the label at i replaced by the given labels
+	replace := func(labels []string, i int, with ...string) []string {
+		return append(append(append([]string{}, labels[:i]...), with...), labels[i+1:]...)
+	}
+
+	for _, name := range names {
+		labels := strings.Split(strings.TrimSuffix(name, "."+zone), ".")
+		for i, label := range labels {
+			for _, numbered := range numberVariants(label) {
+				add(numbered, replace(labels, i, numbered))
+			}
+			parts := strings.Split(label, "-")
+			for j, part := range parts {
+				if !isEnvironmentWord(part) {
+					continue
+				}
+				for _, word := range environmentWords {
+					if word != part {
+						add(word, replace(labels, i, strings.Join(replace(parts, j, word), "-")))
+					}
+				}
+			}
+		}
+	}
+
+	for _, name := range names {
+		labels := strings.Split(strings.TrimSuffix(name, "."+zone), ".")
+		for _, token := range tokens {
+			// Insert the token as a new label at every position
+			for i := 0; i <= len(labels); i++ {
+				add(token, append(append(append([]string{}, labels[:i]...), token), labels[i:]...))
+			}
+			for i, label := range labels {
+				add(token, replace(labels, i, token))
+				add(token, replace(labels, i, label+"-"+token))
+				add(token, replace(labels, i, token+"-"+label))
+				parts := strings.Split(label, "-")
+				if len(parts) > 1 {
+					for j := range parts {
+						add(token, replace(labels, i, strings.Join(replace(parts, j, token), "-")))
+					}
+				}
+			}
+		}
+	}
+	return variants, full
+}
+
+// numberVariants returns the label with each number in it incremented and decremented by one, keeping zero padding.
+func numberVariants(label string) []string {
+	var variants []string
+	for start := 0; start < len(label); {
+		if !unicode.IsDigit(rune(label[start])) {
+			start++
+			continue
+		}
+		end := start
+		for end < len(label) && unicode.IsDigit(rune(label[end])) {
+			end++
+		}
+		digits := label[start:end]
+		if number, err := strconv.Atoi(digits); err == nil {
+			for _, next := range []int{number + 1, number - 1} {
+				if next < 0 {
+					continue
+				}
+				formatted := strconv.Itoa(next)
+				if digits[0] == '0' && len(formatted) < len(digits) {
+					formatted = strings.Repeat("0", len(digits)-len(formatted)) + formatted
+				}
+				variants = append(variants, label[:start]+formatted+label[end:])
+			}
+		}
+		start = end
+	}
+	return variants
+}
+
+func isEnvironmentWord(word string) bool {
+	for _, environmentWord := range environmentWords {
+		if word == environmentWord {
+			return true
+		}
+	}
+	return false
+}
+
+// validLabel reports whether the label is a valid hostname label.
+func validLabel(label string) bool {
+	if label == "" || len(label) > 63 || strings.HasPrefix(label, "-") || strings.HasSuffix(label, "-") {
+		return false
+	}
+	for _, char := range label {
+		if !(char >= 'a' && char <= 'z') && !(char >= '0' && char <= '9') && char != '-' && char != '_' {
+			return false
+		}
+	}
+	return true
+}
+
+func normalizeName(name string) string {
+	return strings.ToLower(strings.Trim(strings.TrimSpace(name), "."))
+}
+
+// ReadSubenumReports returns the names found by previous subdomain enumerations. Each file holds either a bare
+// DnsSubenumReport or the signal osintscan writes with -o json or -o signal, whose content is the report or its base64
+// encoding. Reports of dns certs are read the same way, taking the names on their certificates.
+func ReadSubenumReports(paths []string) ([]string, error) {
+	var names []string
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		var signal struct {
+			Content json.RawMessage `json:"content"`
+		}
+		if err := json.Unmarshal(data, &signal); err != nil {
+			return nil, fmt.Errorf("could not parse report %s: %w", path, err)
+		}
+		if len(signal.Content) > 0 {
+			data = signal.Content
+			var encoded string
+			if json.Unmarshal(signal.Content, &encoded) == nil {
+				data, err = base64.StdEncoding.DecodeString(encoded)
+				if err != nil {
+					return nil, fmt.Errorf("could not decode the content of report %s: %w", path, err)
+				}
+			}
+		}
+
+		var report osintscan.DnsSubenumReport
+		if err := json.Unmarshal(data, &report); err != nil {
+			return nil, fmt.Errorf("could not parse report %s: %w", path, err)
+		}
+		var certs CertsReport
+		if err := json.Unmarshal(data, &certs); err != nil {
+			return nil, fmt.Errorf("could not parse report %s: %w", path, err)
+		}
+		names = append(names, report.Subdomains...)
+		names = append(names, report.NoAddressNames...)
+		names = append(names, report.EmptyNonTerminals...)
+		names = append(names, certificateNames(certs.Certificates)...)
+	}
+	return names, nil
+}
@@ -0,0 +1,175 @@
+package dns
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	osintscan "github.com/Method-Security/osintscan/generated/go"
+)
+
+func TestNumberVariants(t *testing.T) {
+	tests := []struct {
+		label    string
+		variants []string
+	}{
+		{label: "web", variants: nil},
+		{label: "web1", variants: []string{"web2", "web0"}},
+		{label: "web01", variants: []string{"web02", "web00"}},
+		{label: "web009", variants: []string{"web010", "web008"}},
+		{label: "web099", variants: []string{"web100", "web098"}},
+		{label: "web10", variants: []string{"web11", "web9"}},
+		{label: "web0", variants: []string{"web1"}},
+		{label: "web00", variants: []string{"web01"}},
+		{label: "web1-db02", variants: []string{"web2-db02", "web0-db02", "web1-db03", "web1-db01"}},
+		{label: "2fa", variants: []string{"3fa", "1fa"}},
+	}
+	for _, test := range tests {
+		t.Run(test.label, func(t *testing.T) {
+			if variants := numberVariants(test.label); !slices.Equal(variants, test.variants) {
+				t.Errorf("got %v, want %v", variants, test.variants)
+			}
+		})
+	}
+}
+
+func TestGenerateVariants(t *testing.T) {
+	tests := []struct {
+		name    string
+		names   []string
+		tokens  []string
+		limit   int
+		include []variant
+		exclude []string
+		count   int
+		full    bool
+	}{
+		{
+			name:    "numbers keep their padding",
+			names:   []string{"web01.example.com"},
+			limit:   100,
+			include: []variant{{name: "web02.example.com", token: "web02"}, {name: "web00.example.com", token: "web00"}},
+			count:   2,
+		},
+		{
+			name:  "environment labels are swapped",
+			names: []string{"dev.api.example.com"},
+			limit: 100,
+			include: []variant{
+				{name: "staging.api.example.com", token: "staging"},
+				{name: "prod.api.example.com", token: "prod"},
+			},
+			exclude: []string{"dev.api.example.com"},
+			count:   len(environmentWords) - 1,
+		},
+		{
+			name:    "environment parts of a label are swapped",
+			names:   []string{"api-stg.example.com"},
+			limit:   100,
+			include: []variant{{name: "api-prod.example.com", token: "prod"}, {name: "api-uat.example.com", token: "uat"}},
+			count:   len(environmentWords) - 1,
+		},
+		{
+			name:    "known names are not generated again",
+			names:   []string{"api-dev.example.com", "api-prod.example.com"},
+			limit:   100,
+			exclude: []string{"api-dev.example.com", "api-prod.example.com"},
+			count:   len(environmentWords) - 2,
+		},
+		{
+			name:    "words that only contain an environment word are kept",
+			names:   []string{"devices.example.com"},
+			limit:   100,
+			exclude: []string{"prodices.example.com"},
+			count:   0,
+		},
+		{
+			name:   "tokens are inserted after number and environment variants",
+			names:  []string{"api1.example.com"},
+			tokens: []string{"v2"},
+			limit:  100,
+			include: []variant{
+				{name: "api2.example.com", token: "api2"},
+				{name: "v2.api1.example.com", token: "v2"},
+				{name: "api1.v2.example.com", token: "v2"},
+				{name: "api1-v2.example.com", token: "v2"},
+				{name: "v2-api1.example.com", token: "v2"},
+			},
+			count: 7,
+		},
+		{
+			name:  "stops at the limit",
+			names: []string{"dev.example.com"},
+			limit: 3,
+			count: 3,
+			full:  true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			variants, full := generateVariants(test.names, "example.com", test.tokens, test.limit)
+			if full != test.full {
+				t.Errorf("got full %t, want %t", full, test.full)
+			}
+			if len(variants) != test.count {
+				t.Errorf("got %d variants %v, want %d", len(variants), variants, test.count)
+			}
+			position := -1
+			for _, want := range test.include {
+				index := slices.Index(variants, want)
+				if index < 0 {
+					t.Errorf("got variants %v, want %+v", variants, want)
+					continue
+				}
+				if index < position {
+					t.Errorf("got %+v before the variants listed ahead of it", want)
+				}
+				position = index
+			}
+			for _, name := range test.exclude {
+				if slices.ContainsFunc(variants, func(v variant) bool { return v.name == name }) {
+					t.Errorf("got variant %s, want it left out", name)
+				}
+			}
+		})
+	}
+}
+
+func TestPermuteSubdomains(t *testing.T) {
+	engine := startMassResolver(t,
+		`www.example.com. 300 IN A 192.0.2.1`,
+		`web01.example.com. 300 IN A 192.0.2.2`,
+		`web02.example.com. 300 IN A 192.0.2.3`,
+		`www-v2.example.com. 300 IN A 192.0.2.4`,
+		`*.dev.example.com. 300 IN A 192.0.2.99`,
+	)
+	// www was found by another technique and web01 by brute force, and both are already in the report
+	report := osintscan.DnsSubenumReport{Domain: "example.com", Subdomains: []string{}}
+	for _, name := range []string{"www.example.com", "web01.example.com"} {
+		report.Subdomains = append(report.Subdomains, name)
+		report.SubdomainDetails = append(report.SubdomainDetails, newSubdomain("example.com", name, osintscan.SubdomainMethodBrute))
+	}
+	reported := reportedNames(&report)
+
+	errors := permuteSubdomains(context.Background(), &report, reported, []string{"www.example.com", "web01.example.com"}, "example.com", []string{" V2 ", "v2", "dev", "-"}, 100, engine, nil)
+	if len(errors) != 0 {
+		t.Errorf("got errors %v, want none", errors)
+	}
+	// web01.dev only matches the wildcard under dev, and bad tokens are dropped
+	if want := []string{"web01.example.com", "web02.example.com", "www-v2.example.com", "www.example.com"}; !slices.Equal(sorted(report.Subdomains), want) {
+		t.Errorf("got subdomains %v, want %v", report.Subdomains, want)
+	}
+	for _, subdomain := range report.SubdomainDetails[2:] {
+		if subdomain.Method != osintscan.SubdomainMethodPermute || subdomain.Token == nil {
+			t.Errorf("got %s found by %s, want it found by permutation with its token", subdomain.Name, subdomain.Method)
+		}
+	}
+	if len(report.Wildcards) != 1 || report.Wildcards[0].Domain != "dev.example.com" {
+		t.Errorf("got wildcards %v, want the wildcard under dev.example.com", report.Wildcards)
+	}
+
+	errors = permuteSubdomains(context.Background(), &report, reported, []string{"web01.example.com"}, "example.com", nil, 1, engine, nil)
+	if len(errors) != 1 {
+		t.Errorf("got errors %v, want the variants reported as truncated", errors)
+	}
+}
@@ -0,0 +1,221 @@
+package dns
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	osintscan "github.com/Method-Security/osintscan/generated/go"
+	"github.com/miekg/dns"
+)
+
+// announcedPrefixesURL is the RIPEstat endpoint listing the prefixes an ASN announces in the global routing table.
+const announcedPrefixesURL = "https://stat.ripe.net/data/announced-prefixes/data.json?resource=AS%s"
+
+// ptrMaxErrorSamples is how many individual lookup failures are reported before they are only counted.
+const ptrMaxErrorSamples = 10
+
+// SweepPTRRecords looks up the PTR records of every address in the given CIDRs and in the prefixes announced by the given
+// ASNs. Prefixes are expanded up to maxAddresses addresses in total; larger prefixes, which in practice means most IPv6
+// prefixes, are skipped and reported as errors. When suffixes are given only hostnames ending in one of them are kept,
+// so the results can seed subdomain discovery. It returns a PtrSweepReport struct containing the PTR records and any
+// non-fatal errors that occurred.
+func SweepPTRRecords(ctx context.Context, cidrs []string, asns []string, suffixes []string, parallelThreads int, maxAddresses int, resolver *Resolver) (osintscan.PtrSweepReport, error) {
+	errors := []string{}
+	report := osintscan.PtrSweepReport{
+		Cidrs:    cidrs,
+		Asns:     asns,
+		Suffixes: suffixes,
+	}
+
+	prefixes := append([]string{}, cidrs...)
+	for _, asn := range asns {
+		asn = strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(asn)), "AS")
+		announced, err := announcedPrefixes(ctx, asn)
+		if err != nil {
+			errors = append(errors, fmt.Sprintf("could not list the prefixes of AS%s: %s", asn, err.Error()))
+			continue
+		}
+		prefixes = append(prefixes, announced...)
+	}
+
+	addresses, expandErrors := expandPrefixes(prefixes, maxAddresses)
+	errors = append(errors, expandErrors...)
+	if len(addresses) == 0 {
+		return report, fmt.Errorf("no addresses to sweep")
+	}
+	report.AddressCount = len(addresses)
+
+	if parallelThreads <= 0 {
+		parallelThreads = 1
+	}
+	results := make([]*osintscan.PtrRecord, len(addresses))
+	lookupErrors := make([]error, len(addresses))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for worker := 0; worker < min(parallelThreads, len(addresses)); worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				if ctx.Err() != nil {
+					lookupErrors[i] = ctx.Err()
+					continue
+				}
+				results[i], lookupErrors[i] = lookupPTR(ctx, resolver, addresses[i], suffixes)
+			}
+		}()
+	}
+	for i := range addresses {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	failures := 0
+	seen := map[string]bool{}
+	for i, result := range results {
+		if lookupErrors[i] != nil {
+			failures++
+			if failures <= ptrMaxErrorSamples {
+				errors = append(errors, lookupErrors[i].Error())
+			}
+			continue
+		}
+		if result == nil {
+			continue
+		}
+		report.Records = append(report.Records, result)
+		for _, hostname := range result.Hostnames {
+			if !seen[hostname] {
+				seen[hostname] = true
+				report.Hostnames = append(report.Hostnames, hostname)
+			}
+		}
+	}
+	if failures > ptrMaxErrorSamples {
+		errors = append(errors, fmt.Sprintf("%d more PTR lookups failed", failures-ptrMaxErrorSamples))
+	}
+	sort.Strings(report.Hostnames)
+
+	report.Errors = errors
+	return report, nil
+}
+
+// lookupPTR looks up the PTR records of the address and returns the hostnames matching one of the suffixes, or nil when
+// none do.
+func lookupPTR(ctx context.Context, resolver *Resolver, address netip.Addr, suffixes []string) (*osintscan.PtrRecord, error) {
+	records, err := queryRecords(ctx, resolver, address.String(), dns.TypePTR)
+	if err != nil {
+		return nil, err
+	}
+	var hostnames []string
+	ttl := 0
+	for _, record := range records {
+		hostname := strings.ToLower(strings.TrimSuffix(record.Value, "."))
+		if matchesSuffix(hostname, suffixes) {
+			hostnames = append(hostnames, hostname)
+			ttl = record.Ttl
+		}
+	}
+	if len(hostnames) == 0 {
+		return nil, nil
+	}
+	return &osintscan.PtrRecord{Address: address.String(), Hostnames: hostnames, Ttl: ttl}, nil
+}
+
+// expandPrefixes returns every address in the CIDRs and bare IP addresses once, skipping prefixes that are already
+// covered by an earlier one and prefixes that would take the total past maxAddresses.
+func expandPrefixes(prefixes []string, maxAddresses int) ([]netip.Addr, []string) {
+	var addresses []netip.Addr
+	var errors []string
+	var expanded []netip.Prefix
+	seen := map[netip.Addr]bool{}
+	for _, entry := range prefixes {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		prefix, err := netip.ParsePrefix(entry)
+		if err != nil {
+			address, addrErr := netip.ParseAddr(entry)
+			if addrErr != nil {
+				errors = append(errors, fmt.Sprintf("invalid CIDR %q: %s", entry, err.Error()))
+				continue
+			}
+			prefix = netip.PrefixFrom(address, address.BitLen())
+		}
+		prefix = prefix.Masked()
+		if slices.ContainsFunc(expanded, func(earlier netip.Prefix) bool {
+			return earlier.Bits() <= prefix.Bits() && earlier.Contains(prefix.Addr())
+		}) {
+			continue
+		}
+
+		hostBits := prefix.Addr().BitLen() - prefix.Bits()
+		remaining := maxAddresses - len(addresses)
+		if hostBits >= 63 || 1<<hostBits > remaining {
+			errors = append(errors, fmt.Sprintf("skipping %s: it holds more than the %d remaining addresses of the sweep limit", prefix, remaining))
+			continue
+		}
+		expanded = append(expanded, prefix)
+		for address := prefix.Addr(); address.IsValid() && prefix.Contains(address); address = address.Next() {
+			if !seen[address] {
+				seen[address] = true
+				addresses = append(addresses, address)
+			}
+		}
+	}
+	return addresses, errors
+}
+
+func matchesSuffix(hostname string, suffixes []string) bool {
+	if len(suffixes) == 0 {
+		return true
+	}
+	for _, suffix := range suffixes {
+		suffix = strings.ToLower(strings.Trim(strings.TrimSpace(suffix), "."))
+		if suffix != "" && (hostname == suffix || strings.HasSuffix(hostname, "."+suffix)) {
+			return true
+		}
+	}
+	return false
+}
+
+// announcedPrefixes lists the prefixes the ASN currently announces, according to RIPEstat.
+func announcedPrefixes(ctx context.Context, asn string) ([]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf(announcedPrefixesURL, asn), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("RIPEstat returned HTTP %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Data struct {
+			Prefixes []struct {
+				Prefix string `json:"prefix"`
+			} `json:"prefixes"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+	var prefixes []string
+	for _, prefix := range body.Data.Prefixes {
+		prefixes = append(prefixes, prefix.Prefix)
+	}
+	return prefixes, nil
+}
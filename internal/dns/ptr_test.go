@@ -0,0 +1,38 @@
+package dns
+
+import (
+	"testing"
+)
+
+func TestExpandPrefixes(t *testing.T) {
+	tests := []struct {
+		name      string
+		prefixes  []string
+		max       int
+		addresses int
+		errors    int
+	}{
+		{name: "single prefix", prefixes: []string{"192.0.2.0/30"}, max: 10, addresses: 4},
+		{name: "bare address", prefixes: []string{"192.0.2.1"}, max: 10, addresses: 1},
+		{name: "duplicate prefixes", prefixes: []string{"192.0.2.0/30", "192.0.2.1/30"}, max: 10, addresses: 4},
+		{name: "prefix inside an earlier one", prefixes: []string{"192.0.2.0/29", "192.0.2.4/30", "192.0.2.5"}, max: 8, addresses: 8},
+		{name: "prefix around an earlier one", prefixes: []string{"192.0.2.4/30", "192.0.2.0/29"}, max: 20, addresses: 8},
+		{name: "prefix over the limit", prefixes: []string{"192.0.2.0/24", "198.51.100.0/30"}, max: 10, addresses: 4, errors: 1},
+		{name: "invalid entry", prefixes: []string{"example.com", ""}, max: 10, errors: 1},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			addresses, errors := expandPrefixes(test.prefixes, test.max)
+			if len(addresses) != test.addresses || len(errors) != test.errors {
+				t.Errorf("got %d addresses and errors %v, want %d addresses and %d errors", len(addresses), errors, test.addresses, test.errors)
+			}
+			seen := map[string]bool{}
+			for _, address := range addresses {
+				if seen[address.String()] {
+					t.Errorf("got %s more than once", address)
+				}
+				seen[address.String()] = true
+			}
+		})
+	}
+}
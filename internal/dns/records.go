@@ -2,94 +2,298 @@ package dns
 
 import (
 	"context"
+	"errors"
 	"slices"
+	"strings"
+	"time"
 
 	osintscan "github.com/Method-Security/osintscan/generated/go"
 	"github.com/miekg/dns"
-	"github.com/projectdiscovery/dnsx/libs/dnsx"
 )
 
-func getDNSRecords(domain string, questionTypes []uint16) (osintscan.DnsRecords, error) {
-	options := dnsx.DefaultOptions
-	options.QuestionTypes = questionTypes
-	client, err := dnsx.New(options)
-	if err != nil {
-		return osintscan.DnsRecords{}, err
-	}
-
+func getDNSRecords(ctx context.Context, resolver *Resolver, domain string, questionTypes []uint16) (osintscan.DnsRecords, error) {
 	dnsRecords := osintscan.DnsRecords{}
+	var errs []error
 
-	results, err := client.QueryMultiple(domain)
-	if err != nil {
-		return osintscan.DnsRecords{}, err
-	}
-
-	populateRecords := func(records []string, recordType string) []*osintscan.DnsRecord {
-		var dnsRecordsSlice []*osintscan.DnsRecord
-		for _, record := range records {
-			dnsRecord := osintscan.DnsRecord{
-				Name:  domain,
-				Ttl:   int(results.TTL), // This assumes a common TTL for all records; adjust if needed
-				Type:  recordType,
-				Value: record,
-			}
-			dnsRecordsSlice = append(dnsRecordsSlice, &dnsRecord)
+	for _, questionType := range questionTypes {
+		records, err := queryRecords(ctx, resolver, domain, questionType)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		switch questionType {
+		case dns.TypeA:
+			dnsRecords.A = records
+		case dns.TypeAAAA:
+			dnsRecords.Aaaa = records
+		case dns.TypeCNAME:
+			dnsRecords.Cname = records
+		case dns.TypeMX:
+			dnsRecords.Mx = records
+		case dns.TypeNS:
+			dnsRecords.Ns = records
+		case dns.TypeTXT:
+			dnsRecords.Txt = records
+		case dns.TypeSOA:
+			dnsRecords.Soa = records
+		case dns.TypeCAA:
+			dnsRecords.Caa = records
+		case dns.TypeSRV:
+			dnsRecords.Srv = records
+		case dns.TypePTR:
+			dnsRecords.Ptr = records
+		case dns.TypeDNSKEY:
+			dnsRecords.Dnskey = records
+		case dns.TypeDS:
+			dnsRecords.Ds = records
+		case dns.TypeTLSA:
+			dnsRecords.Tlsa = records
+		case dns.TypeHTTPS:
+			dnsRecords.Https = records
+		case dns.TypeSVCB:
+			dnsRecords.Svcb = records
+		case dns.TypeNAPTR:
+			dnsRecords.Naptr = records
 		}
-		return dnsRecordsSlice
 	}
 
-	if slices.Contains(questionTypes, dns.TypeA) {
-		dnsRecords.A = populateRecords(results.A, "A")
+	return dnsRecords, errors.Join(errs...)
+}
+
+// srvServices are the well-known _service._proto labels whose SRV records are looked up under the domain.
+var srvServices = []string{
+	"_sip._tcp", "_sip._udp", "_sips._tcp", "_sipfederationtls._tcp",
+	"_xmpp-client._tcp", "_xmpp-server._tcp",
+	"_ldap._tcp", "_kerberos._tcp", "_kerberos._udp",
+	"_autodiscover._tcp", "_submission._tcp", "_imaps._tcp", "_pop3s._tcp",
+	"_caldavs._tcp", "_carddavs._tcp",
+}
+
+// getOwnerRecords queries every owner name for records of the given type and returns the records found under all of
+// them. Each record keeps the owner name it was found under.
+func getOwnerRecords(ctx context.Context, resolver *Resolver, owners []string, questionType uint16) ([]*osintscan.DnsRecord, error) {
+	var records []*osintscan.DnsRecord
+	var errs []error
+	for _, owner := range owners {
+		ownerRecords, err := queryRecords(ctx, resolver, owner, questionType)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		records = append(records, ownerRecords...)
 	}
-	if slices.Contains(questionTypes, dns.TypeAAAA) {
-		dnsRecords.Aaaa = populateRecords(results.AAAA, "AAAA")
+	return records, errors.Join(errs...)
+}
+
+// queryRecords sends a single question for the given type through the resolver and returns the answers of that type.
+func queryRecords(ctx context.Context, resolver *Resolver, domain string, questionType uint16) ([]*osintscan.DnsRecord, error) {
+	resp, err := resolver.Query(ctx, domain, questionType)
+	if err != nil {
+		return nil, err
 	}
-	if slices.Contains(questionTypes, dns.TypeCNAME) {
-		dnsRecords.Cname = populateRecords(results.CNAME, "CNAME")
+
+	// Every record in the answer shares the metadata of the response that carried it, which lets consumers
+	// compare evidence gathered from different resolvers
+	metadata := responseMetadata(resp.Msg, resp.Server, resp.Rtt)
+	var records []*osintscan.DnsRecord
+	for _, rr := range resp.Msg.Answer {
+		if rr.Header().Rrtype != questionType {
+			continue
+		}
+		record := dnsRecordFromRR(rr)
+		record.Response = metadata
+		records = append(records, record)
 	}
-	if slices.Contains(questionTypes, dns.TypeMX) {
-		dnsRecords.Mx = populateRecords(results.MX, "MX")
+	return records, nil
+}
+
+// responseMetadata captures which server answered a query and how it answered.
+func responseMetadata(resp *dns.Msg, server string, rtt time.Duration) *osintscan.DnsResponseMetadata {
+	return &osintscan.DnsResponseMetadata{
+		Resolver:          server,
+		Authoritative:     resp.Authoritative,
+		AuthenticatedData: resp.AuthenticatedData,
+		Rcode:             dns.RcodeToString[resp.Rcode],
+		QueryTimeMs:       int(rtt.Milliseconds()),
 	}
-	if slices.Contains(questionTypes, dns.TypeNS) {
-		dnsRecords.Ns = populateRecords(results.NS, "NS")
+}
+
+// dnsRecordFromRR converts a miekg/dns resource record into a DnsRecord. The value always carries the presentation
+// format of the record data, while record types with structured data also populate their typed field.
+func dnsRecordFromRR(rr dns.RR) *osintscan.DnsRecord {
+	header := rr.Header()
+	dnsRecord := osintscan.DnsRecord{
+		Name:  strings.TrimSuffix(header.Name, "."),
+		Ttl:   int(header.Ttl),
+		Type:  dns.TypeToString[header.Rrtype],
+		Value: strings.TrimSpace(strings.TrimPrefix(rr.String(), header.String())),
 	}
-	if slices.Contains(questionTypes, dns.TypeTXT) {
-		dnsRecords.Txt = populateRecords(results.TXT, "TXT")
+
+	switch record := rr.(type) {
+	case *dns.A:
+		dnsRecord.Value = record.A.String()
+	case *dns.AAAA:
+		dnsRecord.Value = record.AAAA.String()
+	case *dns.CNAME:
+		dnsRecord.Value = strings.TrimSuffix(record.Target, ".")
+	case *dns.NS:
+		dnsRecord.Value = strings.TrimSuffix(record.Ns, ".")
+	case *dns.PTR:
+		dnsRecord.Value = strings.TrimSuffix(record.Ptr, ".")
+	case *dns.TXT:
+		// Long TXT records (e.g. DKIM keys) are split into 255 byte strings which only make sense joined together
+		dnsRecord.Value = strings.Join(record.Txt, "")
+	case *dns.MX:
+		dnsRecord.Value = strings.TrimSuffix(record.Mx, ".")
+		dnsRecord.Mx = &osintscan.DnsMxData{
+			Preference: int(record.Preference),
+			Exchange:   strings.TrimSuffix(record.Mx, "."),
+		}
+	case *dns.SOA:
+		dnsRecord.Soa = &osintscan.DnsSoaData{
+			Mname:   strings.TrimSuffix(record.Ns, "."),
+			Rname:   strings.TrimSuffix(record.Mbox, "."),
+			Serial:  int64(record.Serial),
+			Refresh: int(record.Refresh),
+			Retry:   int(record.Retry),
+			Expire:  int(record.Expire),
+			Minimum: int(record.Minttl),
+		}
+	case *dns.CAA:
+		dnsRecord.Caa = &osintscan.DnsCaaData{
+			Flag:  int(record.Flag),
+			Tag:   record.Tag,
+			Value: record.Value,
+		}
+	case *dns.SRV:
+		dnsRecord.Srv = &osintscan.DnsSrvData{
+			Priority: int(record.Priority),
+			Weight:   int(record.Weight),
+			Port:     int(record.Port),
+			Target:   strings.TrimSuffix(record.Target, "."),
+		}
+	case *dns.DNSKEY:
+		dnsRecord.Dnskey = &osintscan.DnsDnskeyData{
+			Flags:         int(record.Flags),
+			Protocol:      int(record.Protocol),
+			Algorithm:     int(record.Algorithm),
+			AlgorithmName: dns.AlgorithmToString[record.Algorithm],
+			KeyTag:        int(record.KeyTag()),
+			PublicKey:     record.PublicKey,
+		}
+	case *dns.DS:
+		dnsRecord.Ds = &osintscan.DnsDsData{
+			KeyTag:        int(record.KeyTag),
+			Algorithm:     int(record.Algorithm),
+			AlgorithmName: dns.AlgorithmToString[record.Algorithm],
+			DigestType:    int(record.DigestType),
+			Digest:        record.Digest,
+		}
+	case *dns.TLSA:
+		dnsRecord.Tlsa = &osintscan.DnsTlsaData{
+			Usage:        int(record.Usage),
+			Selector:     int(record.Selector),
+			MatchingType: int(record.MatchingType),
+			Certificate:  record.Certificate,
+		}
+	case *dns.HTTPS:
+		dnsRecord.Svcb = svcbData(&record.SVCB)
+	case *dns.SVCB:
+		dnsRecord.Svcb = svcbData(record)
+	case *dns.NAPTR:
+		dnsRecord.Naptr = &osintscan.DnsNaptrData{
+			Order:       int(record.Order),
+			Preference:  int(record.Preference),
+			Flags:       record.Flags,
+			Service:     record.Service,
+			Regexp:      record.Regexp,
+			Replacement: strings.TrimSuffix(record.Replacement, "."),
+		}
 	}
 
-	return dnsRecords, nil
+	return &dnsRecord
+}
+
+func svcbData(record *dns.SVCB) *osintscan.DnsSvcbData {
+	data := osintscan.DnsSvcbData{
+		Priority: int(record.Priority),
+		Target:   strings.TrimSuffix(record.Target, "."),
+	}
+	if len(record.Value) > 0 {
+		data.Params = make(map[string]string, len(record.Value))
+		for _, keyValue := range record.Value {
+			data.Params[keyValue.Key().String()] = keyValue.String()
+		}
+	}
+	return &data
 }
 
-// GetDomainDNSRecords queries DNS for all records for a given domain. It returns a RecordsReport struct containing
-// all records that were and any non-fatal errors that occurred.
-func GetDomainDNSRecords(ctx context.Context, domain string) (osintscan.DnsRecordsReport, error) {
+// GetDomainDNSRecords queries DNS for all records for a given domain through the given resolver, brute-forcing the
+// given DKIM selectors with dkimThreads parallel queries. It returns a RecordsReport struct containing all records that
+// were and any non-fatal errors that occurred.
+func GetDomainDNSRecords(ctx context.Context, domain string, resolver *Resolver, dkimSelectors []string, dkimThreads int) (osintscan.DnsRecordsReport, error) {
 	errors := []string{}
 
-	// Get all the DNS records
-	var questionTypes []uint16 = []uint16{dns.TypeA, dns.TypeAAAA, dns.TypeMX, dns.TypeTXT, dns.TypeNS, dns.TypeCNAME}
-	dnsRecords, err := getDNSRecords(domain, questionTypes)
+	// Get all the DNS records. SRV and TLSA records only exist under service owner names, so they are queried there
+	// rather than at the domain itself
+	var questionTypes []uint16 = []uint16{
+		dns.TypeA, dns.TypeAAAA, dns.TypeMX, dns.TypeTXT, dns.TypeNS, dns.TypeCNAME,
+		dns.TypeSOA, dns.TypeCAA, dns.TypeDNSKEY, dns.TypeDS, dns.TypeHTTPS, dns.TypeSVCB, dns.TypeNAPTR,
+	}
+	dnsRecords, err := getDNSRecords(ctx, resolver, domain, questionTypes)
+	if err != nil {
+		errors = append(errors, err.Error())
+	}
+	var srvOwners []string
+	for _, service := range srvServices {
+		srvOwners = append(srvOwners, service+"."+domain)
+	}
+	dnsRecords.Srv, err = getOwnerRecords(ctx, resolver, srvOwners, dns.TypeSRV)
+	if err != nil {
+		errors = append(errors, err.Error())
+	}
+	tlsaOwners := []string{"_443._tcp." + domain, "_443._tcp.www." + domain}
+	for _, mxRecord := range dnsRecords.Mx {
+		if mxRecord.Mx != nil && mxRecord.Mx.Exchange != "" {
+			tlsaOwners = append(tlsaOwners, "_25._tcp."+mxRecord.Mx.Exchange)
+		}
+	}
+	dnsRecords.Tlsa, err = getOwnerRecords(ctx, resolver, tlsaOwners, dns.TypeTLSA)
+	if err != nil {
+		errors = append(errors, err.Error())
+	}
+	// PTR records live in the reverse zones, so they are looked up under the reverse names of the domain's addresses
+	var ptrOwners []string
+	for _, addressRecord := range slices.Concat(dnsRecords.A, dnsRecords.Aaaa) {
+		if reverse, err := dns.ReverseAddr(addressRecord.Value); err == nil {
+			ptrOwners = append(ptrOwners, reverse)
+		}
+	}
+	dnsRecords.Ptr, err = getOwnerRecords(ctx, resolver, ptrOwners, dns.TypePTR)
 	if err != nil {
 		errors = append(errors, err.Error())
 	}
 
 	// The DMARC record is always in the _dmarc subdomain (RFC-7489) and therefore must be fetched separately
-	dmarcRecords, err := getDNSRecords("_dmarc."+domain, []uint16{dns.TypeTXT})
+	dmarcRecords, err := getDNSRecords(ctx, resolver, "_dmarc."+domain, []uint16{dns.TypeTXT})
 	if err != nil {
 		errors = append(errors, err.Error())
 	}
 
 	// The DKIM record is always in the _domainkey subdomain (RFC-6376) and therefore must be fetched separately.
 	// To complicate matters, the _domainkey subdomain itself includes a subdomain named after a selector which we
-	// don't know in advance, so we need to check each common selector that we're aware of.
-	dkimRecords := osintscan.DnsRecords{}
-	var selectors []string = []string{"default", "selector1", "selector2", "google", "amazonses", "microsoft"}
-	for _, selector := range selectors {
-		dkimRecordForSelector, err := getDNSRecords(selector+"._domainkey."+domain, []uint16{dns.TypeTXT})
-		if err != nil {
-			errors = append(errors, err.Error())
-		}
-		dkimRecords.Txt = append(dkimRecords.Txt, dkimRecordForSelector.Txt...)
-	}
+	// don't know in advance, so we need to brute-force the selectors from the provided wordlist.
+	dkimTxtRecords, dkimKeys, dkimErrors := discoverDKIM(ctx, resolver, domain, dkimSelectors, dkimThreads)
+	errors = append(errors, dkimErrors...)
+	dkimRecords := osintscan.DnsRecords{Txt: dkimTxtRecords}
+
+	// Expand the SPF policy published in the domain's TXT records, including its include and redirect chain
+	spfPolicy := analyzeSPF(ctx, resolver, domain, dnsRecords.Txt)
+
+	// Parse the DMARC policy that governs the domain and combine it with SPF to judge whether it can be spoofed
+	dmarcPolicy := analyzeDMARC(ctx, resolver, domain, dmarcRecords.Txt)
+	spoofability := assessSpoofability(spfPolicy, dmarcPolicy)
 
 	// Create report and write to file
 	report := osintscan.DnsRecordsReport{
@@ -97,6 +301,10 @@ func GetDomainDNSRecords(ctx context.Context, domain string) (osintscan.DnsRecor
 		DnsRecords:      &dnsRecords,
 		DmarcDnsRecords: &dmarcRecords,
 		DkimDnsRecords:  &dkimRecords,
+		DkimKeys:        dkimKeys,
+		SpfPolicy:       spfPolicy,
+		DmarcPolicy:     dmarcPolicy,
+		Spoofability:    spoofability,
 		Errors:          errors,
 	}
 
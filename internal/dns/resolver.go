@@ -0,0 +1,316 @@
+package dns
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/projectdiscovery/dnsx/libs/dnsx"
+)
+
+// ErrNoAnswer is returned by the lookup helpers when the queried name exists but holds no data of the requested type,
+// or when it does not exist at all.
+var ErrNoAnswer = errors.New("no answer")
+
+// ResolverConfig contains the configuration used to build a Resolver. Servers accepts plain addresses (1.1.1.1 or
+// 1.1.1.1:53), protocol prefixed addresses (udp:1.1.1.1:53, tcp:1.1.1.1:53), DNS-over-TLS endpoints (tls://1.1.1.1 or
+// tls://dns.google:853) and DNS-over-HTTPS endpoints (https://cloudflare-dns.com/dns-query). A zero Timeout or Retries
+// is replaced by its default of 5 seconds or 3 attempts.
+type ResolverConfig struct {
+	Servers []string
+	Timeout time.Duration
+	Retries int
+}
+
+// Response is a DNS response along with the server that produced it and how long the exchange took.
+type Response struct {
+	Msg    *dns.Msg
+	Server string
+	Rtt    time.Duration
+}
+
+// Resolver sends DNS queries to a pool of upstream servers. Each query starts at the next server in the pool so load is
+// spread across all of them, and failed queries are retried against the following server.
+type Resolver struct {
+	upstreams []upstream
+	timeout   time.Duration
+	retries   int
+	next      uint32
+}
+
+type upstream interface {
+	exchange(ctx context.Context, msg *dns.Msg) (*dns.Msg, time.Duration, error)
+	String() string
+}
+
+// NewResolver creates a Resolver from the given configuration. When no servers are configured the dnsx default public
+// resolvers are used so that results do not depend on the host's resolver configuration.
+func NewResolver(config ResolverConfig) (*Resolver, error) {
+	servers := config.Servers
+	if len(servers) == 0 {
+		servers = dnsx.DefaultResolvers
+	}
+	timeout := config.Timeout
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	retries := config.Retries
+	if retries <= 0 {
+		retries = 3
+	}
+
+	resolver := &Resolver{timeout: timeout, retries: retries}
+	for _, server := range servers {
+		server = strings.TrimSpace(server)
+		if server == "" || strings.HasPrefix(server, "#") {
+			continue
+		}
+		upstream, err := parseUpstream(server, timeout)
+		if err != nil {
+			return nil, err
+		}
+		resolver.upstreams = append(resolver.upstreams, upstream)
+	}
+	if len(resolver.upstreams) == 0 {
+		return nil, errors.New("no resolvers configured")
+	}
+	return resolver, nil
+}
+
+// Servers returns the string form of every upstream server in the pool.
+func (r *Resolver) Servers() []string {
+	servers := make([]string, 0, len(r.upstreams))
+	for _, upstream := range r.upstreams {
+		servers = append(servers, upstream.String())
+	}
+	return servers
+}
+
+// Exchange sends the message to the pool, rotating to the next server whenever an exchange fails or the server answers
+// with SERVFAIL or REFUSED, making at most as many attempts as the configured retries. Other rcodes, including NXDOMAIN,
+// are considered valid answers and returned to the caller.
+func (r *Resolver) Exchange(ctx context.Context, msg *dns.Msg) (*Response, error) {
+	start := atomic.AddUint32(&r.next, 1)
+	var lastErr error
+	for i := 0; i < r.retries; i++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		upstream := r.upstreams[(int(start)+i)%len(r.upstreams)]
+		resp, rtt, err := upstream.exchange(ctx, msg.Copy())
+		if err != nil {
+			lastErr = fmt.Errorf("%s: %w", upstream, err)
+			continue
+		}
+		if resp.Rcode == dns.RcodeServerFailure || resp.Rcode == dns.RcodeRefused {
+			lastErr = fmt.Errorf("%s: %s query for %s returned %s", upstream, dns.TypeToString[msg.Question[0].Qtype], msg.Question[0].Name, dns.RcodeToString[resp.Rcode])
+			continue
+		}
+		return &Response{Msg: resp, Server: upstream.String(), Rtt: rtt}, nil
+	}
+	return nil, lastErr
+}
+
+// Query builds a recursive question for the given name and type and sends it to the pool. PTR questions for IP
+// addresses are converted to their reverse name.
+func (r *Resolver) Query(ctx context.Context, name string, questionType uint16) (*Response, error) {
+	fqdn := dns.Fqdn(name)
+	if questionType == dns.TypePTR && net.ParseIP(name) != nil {
+		reverseName, err := dns.ReverseAddr(name)
+		if err != nil {
+			return nil, err
+		}
+		fqdn = reverseName
+	}
+
+	msg := &dns.Msg{}
+	msg.SetQuestion(fqdn, questionType)
+	msg.SetEdns0(4096, true)
+	return r.Exchange(ctx, msg)
+}
+
+// LookupHost returns the IPv4 and IPv6 addresses of the given host, mirroring net.Resolver.LookupHost.
+func (r *Resolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	var addresses []string
+	var errs []error
+	for _, questionType := range []uint16{dns.TypeA, dns.TypeAAAA} {
+		resp, err := r.Query(ctx, host, questionType)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for _, rr := range resp.Msg.Answer {
+			switch record := rr.(type) {
+			case *dns.A:
+				addresses = append(addresses, record.A.String())
+			case *dns.AAAA:
+				addresses = append(addresses, record.AAAA.String())
+			}
+		}
+	}
+	if len(addresses) > 0 {
+		return addresses, nil
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return nil, fmt.Errorf("%s: %w", host, ErrNoAnswer)
+}
+
+// LookupCNAME follows the CNAME chain of the given host and returns the fully qualified name at the end of it, mirroring
+// net.Resolver.LookupCNAME. Hosts without a CNAME return their own name.
+func (r *Resolver) LookupCNAME(ctx context.Context, host string) (string, error) {
+	resp, err := r.Query(ctx, host, dns.TypeA)
+	if err != nil {
+		return "", err
+	}
+
+	canonical := dns.Fqdn(host)
+	for _, rr := range resp.Msg.Answer {
+		if cname, ok := rr.(*dns.CNAME); ok && strings.EqualFold(cname.Hdr.Name, canonical) {
+			canonical = cname.Target
+		}
+	}
+	if canonical == dns.Fqdn(host) && resp.Msg.Rcode == dns.RcodeNameError {
+		return "", fmt.Errorf("%s: %w", host, ErrNoAnswer)
+	}
+	return canonical, nil
+}
+
+func parseUpstream(server string, timeout time.Duration) (upstream, error) {
+	switch {
+	case strings.HasPrefix(server, "https://"):
+		return &dohUpstream{
+			url:    server,
+			client: &http.Client{Timeout: timeout},
+		}, nil
+	case strings.HasPrefix(server, "tls://"), strings.HasPrefix(server, "tls:"):
+		address := strings.TrimPrefix(strings.TrimPrefix(server, "tls://"), "tls:")
+		address = withDefaultPort(address, "853")
+		host, _, err := net.SplitHostPort(address)
+		if err != nil {
+			return nil, err
+		}
+		return &dnsUpstream{
+			network: "tcp-tls",
+			address: address,
+			client: &dns.Client{
+				Net:       "tcp-tls",
+				Timeout:   timeout,
+				TLSConfig: &tls.Config{ServerName: host, MinVersion: tls.VersionTLS12},
+			},
+		}, nil
+	case strings.HasPrefix(server, "tcp://"), strings.HasPrefix(server, "tcp:"):
+		address := withDefaultPort(strings.TrimPrefix(strings.TrimPrefix(server, "tcp://"), "tcp:"), "53")
+		return &dnsUpstream{
+			network: "tcp",
+			address: address,
+			client:  &dns.Client{Net: "tcp", Timeout: timeout},
+		}, nil
+	default:
+		address := withDefaultPort(strings.TrimPrefix(strings.TrimPrefix(server, "udp://"), "udp:"), "53")
+		if _, _, err := net.SplitHostPort(address); err != nil {
+			return nil, fmt.Errorf("invalid resolver %q: %w", server, err)
+		}
+		return &dnsUpstream{
+			network:  "udp",
+			address:  address,
+			client:   &dns.Client{Net: "udp", Timeout: timeout},
+			fallback: &dns.Client{Net: "tcp", Timeout: timeout},
+		}, nil
+	}
+}
+
+// withDefaultPort appends the port to the address unless it already has one. Bare IPv6 addresses are bracketed.
+func withDefaultPort(address string, port string) string {
+	if _, _, err := net.SplitHostPort(address); err == nil {
+		return address
+	}
+	return net.JoinHostPort(strings.Trim(address, "[]"), port)
+}
+
+// dnsUpstream is a classic DNS server reached over UDP, TCP or TLS. UDP servers fall back to TCP on truncation.
+type dnsUpstream struct {
+	network  string
+	address  string
+	client   *dns.Client
+	fallback *dns.Client
+}
+
+func (u *dnsUpstream) exchange(ctx context.Context, msg *dns.Msg) (*dns.Msg, time.Duration, error) {
+	resp, rtt, err := u.client.ExchangeContext(ctx, msg, u.address)
+	if err == nil && resp.Truncated && u.fallback != nil {
+		resp, rtt, err = u.fallback.ExchangeContext(ctx, msg, u.address)
+	}
+	return resp, rtt, err
+}
+
+func (u *dnsUpstream) String() string {
+	switch u.network {
+	case "tcp-tls":
+		return "tls://" + u.address
+	case "tcp":
+		return "tcp:" + u.address
+	default:
+		return u.address
+	}
+}
+
+// dohUpstream is a DNS-over-HTTPS server queried with RFC 8484 POST requests.
+type dohUpstream struct {
+	url    string
+	client *http.Client
+}
+
+func (u *dohUpstream) exchange(ctx context.Context, msg *dns.Msg) (*dns.Msg, time.Duration, error) {
+	// RFC 8484 recommends a zero ID to make responses cache friendly; restore the original ID afterwards
+	id := msg.Id
+	msg.Id = 0
+	packed, err := msg.Pack()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.url, bytes.NewReader(packed))
+	if err != nil {
+		return nil, 0, err
+	}
+	req.Header.Set("Content-Type", "application/dns-message")
+	req.Header.Set("Accept", "application/dns-message")
+
+	start := time.Now()
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	rtt := time.Since(start)
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, rtt, fmt.Errorf("unexpected HTTP status %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, dns.MaxMsgSize))
+	if err != nil {
+		return nil, rtt, err
+	}
+
+	answer := &dns.Msg{}
+	if err := answer.Unpack(body); err != nil {
+		return nil, rtt, err
+	}
+	answer.Id = id
+	return answer, rtt, nil
+}
+
+func (u *dohUpstream) String() string {
+	return u.url
+}
@@ -0,0 +1,215 @@
+package dns
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// testServer is a DNS server on 127.0.0.1 answering every query according to its mode over both UDP and TCP.
+type testServer struct {
+	address string
+	mode    string
+	queries atomic.Int32
+}
+
+func startTestServer(t *testing.T, mode string) *testServer {
+	t.Helper()
+	server := &testServer{mode: mode}
+	server.address = serveDNS(t, dns.HandlerFunc(func(w dns.ResponseWriter, req *dns.Msg) {
+		server.queries.Add(1)
+		resp := &dns.Msg{}
+		resp.SetReply(req)
+		answer := &dns.A{
+			Hdr: dns.RR_Header{Name: req.Question[0].Name, Rrtype: dns.TypeA, Class: dns.ClassINET, Ttl: 60},
+			A:   net.ParseIP("192.0.2.1"),
+		}
+		switch server.mode {
+		case "servfail":
+			resp.Rcode = dns.RcodeServerFailure
+		case "refused":
+			resp.Rcode = dns.RcodeRefused
+		case "nxdomain":
+			resp.Rcode = dns.RcodeNameError
+		case "truncate":
+			if _, udp := w.RemoteAddr().(*net.UDPAddr); udp {
+				resp.Truncated = true
+			} else {
+				resp.Answer = append(resp.Answer, answer)
+			}
+		default:
+			resp.Answer = append(resp.Answer, answer)
+		}
+		_ = w.WriteMsg(resp)
+	}))
+	return server
+}
+
+// serveDNS serves the handler over UDP and TCP on the same port of 127.0.0.1 until the test ends, and returns the
+// address it listens on.
+func serveDNS(t *testing.T, handler dns.Handler) string {
+	t.Helper()
+	packetConn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	listener, err := net.Listen("tcp", packetConn.LocalAddr().String())
+	if err != nil {
+		_ = packetConn.Close()
+		t.Fatal(err)
+	}
+
+	for _, dnsServer := range []*dns.Server{
+		{PacketConn: packetConn, Handler: handler},
+		{Listener: listener, Handler: handler},
+	} {
+		started := make(chan struct{})
+		dnsServer.NotifyStartedFunc = func() { close(started) }
+		go func() { _ = dnsServer.ActivateAndServe() }()
+		<-started
+		t.Cleanup(func() { _ = dnsServer.Shutdown() })
+	}
+	return packetConn.LocalAddr().String()
+}
+
+// startZoneServer starts a server on 127.0.0.1 that answers like a recursive resolver from the records, given in zone
+// file format. CNAMEs are followed within the records, ANY queries are answered with every record of the name,
+// wildcards answer for the names below their parent that own no records, names that only have names below them exist
+// without data, and every other name does not exist. It returns a Resolver that queries the server.
+func startZoneServer(t *testing.T, records ...string) *Resolver {
+	t.Helper()
+	owners := map[string][]dns.RR{}
+	for _, record := range records {
+		rr, err := dns.NewRR(record)
+		if err != nil {
+			t.Fatal(err)
+		}
+		owner := dns.CanonicalName(rr.Header().Name)
+		owners[owner] = append(owners[owner], rr)
+	}
+	exists := func(name string) bool {
+		for owner := range owners {
+			if owner == name || dns.IsSubDomain(name, owner) {
+				return true
+			}
+		}
+		return false
+	}
+
+	address := serveDNS(t, dns.HandlerFunc(func(w dns.ResponseWriter, req *dns.Msg) {
+		resp := &dns.Msg{}
+		resp.SetReply(req)
+		resp.RecursionAvailable = true
+		question := req.Question[0]
+		name := dns.CanonicalName(question.Name)
+		for hops := 0; hops < 8; hops++ {
+			rrs := owners[name]
+			if !exists(name) {
+				_, parent, _ := strings.Cut(name, ".")
+				for _, rr := range owners["*."+parent] {
+					rr = dns.Copy(rr)
+					rr.Header().Name = name
+					rrs = append(rrs, rr)
+				}
+				if len(rrs) == 0 {
+					resp.Rcode = dns.RcodeNameError
+					break
+				}
+			}
+			found := false
+			var cname *dns.CNAME
+			for _, rr := range rrs {
+				if rr.Header().Rrtype == question.Qtype || question.Qtype == dns.TypeANY {
+					found = true
+					resp.Answer = append(resp.Answer, rr)
+				} else if record, ok := rr.(*dns.CNAME); ok {
+					cname = record
+				}
+			}
+			if found || cname == nil {
+				break
+			}
+			resp.Answer = append(resp.Answer, cname)
+			name = dns.CanonicalName(cname.Target)
+		}
+		_ = w.WriteMsg(resp)
+	}))
+
+	resolver, err := NewResolver(ResolverConfig{Servers: []string{address}, Timeout: time.Second, Retries: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resolver
+}
+
+func TestResolverExchange(t *testing.T) {
+	tests := []struct {
+		name    string
+		modes   []string
+		retries int
+		rcode   int
+		answers int
+		server  int
+		queries int32
+		wantErr bool
+	}{
+		{name: "rotates past SERVFAIL", modes: []string{"answer", "servfail"}, retries: 2, answers: 1, server: 0, queries: 2},
+		{name: "rotates past REFUSED", modes: []string{"answer", "refused"}, retries: 2, answers: 1, server: 0, queries: 2},
+		{name: "fails when every server refuses", modes: []string{"servfail", "refused"}, retries: 3, queries: 3, wantErr: true},
+		{name: "stops at the configured retries", modes: []string{"servfail", "servfail", "answer", "servfail"}, retries: 2, queries: 2, wantErr: true},
+		{name: "returns NXDOMAIN", modes: []string{"answer", "nxdomain"}, retries: 2, rcode: dns.RcodeNameError, server: 1, queries: 1},
+		{name: "falls back to TCP when truncated", modes: []string{"truncate"}, retries: 1, answers: 1, server: 0, queries: 2},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var servers []*testServer
+			var addresses []string
+			for _, mode := range test.modes {
+				server := startTestServer(t, mode)
+				servers = append(servers, server)
+				addresses = append(addresses, server.address)
+			}
+			resolver, err := NewResolver(ResolverConfig{Servers: addresses, Retries: test.retries})
+			if err != nil {
+				t.Fatal(err)
+			}
+			// Every query starts at the next server, so start the pool at the last server to make the order predictable
+			resolver.next = uint32(len(addresses) - 2)
+
+			msg := &dns.Msg{}
+			msg.SetQuestion("www.example.com.", dns.TypeA)
+			resp, err := resolver.Exchange(context.Background(), msg)
+
+			var queries int32
+			for _, server := range servers {
+				queries += server.queries.Load()
+			}
+			if queries != test.queries {
+				t.Errorf("sent %d queries, want %d", queries, test.queries)
+			}
+			if test.wantErr {
+				if err == nil {
+					t.Fatalf("got a %s answer from %s, want an error", dns.RcodeToString[resp.Msg.Rcode], resp.Server)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.Msg.Rcode != test.rcode {
+				t.Errorf("got rcode %s, want %s", dns.RcodeToString[resp.Msg.Rcode], dns.RcodeToString[test.rcode])
+			}
+			if len(resp.Msg.Answer) != test.answers {
+				t.Errorf("got %d answers, want %d", len(resp.Msg.Answer), test.answers)
+			}
+			if resp.Server != addresses[test.server] {
+				t.Errorf("answered by %s, want %s", resp.Server, addresses[test.server])
+			}
+		})
+	}
+}
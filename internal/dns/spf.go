@@ -0,0 +1,372 @@
+package dns
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"strconv"
+	"strings"
+
+	osintscan "github.com/Method-Security/osintscan/generated/go"
+	"github.com/miekg/dns"
+)
+
+const (
+	// spfLookupLimit is the maximum number of DNS-querying terms an SPF evaluation may use (RFC 7208 section 4.6.4)
+	spfLookupLimit = 10
+	// spfVoidLookupLimit is the maximum number of lookups returning no answer an SPF evaluation may use (RFC 7208 section 4.6.4)
+	spfVoidLookupLimit = 2
+	// spfMaxDepth bounds include/redirect recursion so that pathological records cannot stall the scan
+	spfMaxDepth = 20
+)
+
+var spfQualifiers = map[byte]osintscan.SpfQualifier{
+	'+': osintscan.SpfQualifierPass,
+	'-': osintscan.SpfQualifierFail,
+	'~': osintscan.SpfQualifierSoftfail,
+	'?': osintscan.SpfQualifierNeutral,
+}
+
+// spfAnalyzer walks an SPF policy and its include/redirect chain, accumulating lookup counts and authorized ranges as
+// it goes. The DNS lookup limits apply to the evaluation as a whole, which is why this state is shared across the tree.
+type spfAnalyzer struct {
+	ctx      context.Context
+	resolver *Resolver
+	policy   *osintscan.SpfPolicy
+	visiting map[string]bool
+	ranges   map[string]struct{}
+}
+
+// isSPFRecord reports whether the TXT record is an SPF version 1 record.
+func isSPFRecord(txt string) bool {
+	lower := strings.ToLower(strings.TrimSpace(txt))
+	return lower == "v=spf1" || strings.HasPrefix(lower, "v=spf1 ")
+}
+
+// analyzeSPF parses the SPF policy published in the given TXT records of a domain and recursively expands its include
+// and redirect chain into a tree.
+func analyzeSPF(ctx context.Context, resolver *Resolver, domain string, txtRecords []*osintscan.DnsRecord) *osintscan.SpfPolicy {
+	var spfRecords []string
+	for _, txtRecord := range txtRecords {
+		if isSPFRecord(txtRecord.Value) {
+			spfRecords = append(spfRecords, txtRecord.Value)
+		}
+	}
+
+	policy := &osintscan.SpfPolicy{
+		Domain:           domain,
+		RecordCount:      len(spfRecords),
+		DuplicateRecords: len(spfRecords) > 1,
+	}
+	if len(spfRecords) == 0 {
+		policy.Findings = append(policy.Findings, "no SPF record published")
+		return policy
+	}
+	if policy.DuplicateRecords {
+		policy.Findings = append(policy.Findings, fmt.Sprintf("%d SPF records published; multiple records cause a permerror (RFC 7208 section 4.5)", len(spfRecords)))
+	}
+
+	analyzer := &spfAnalyzer{
+		ctx:      ctx,
+		resolver: resolver,
+		policy:   policy,
+		visiting: map[string]bool{},
+		ranges:   map[string]struct{}{},
+	}
+	policy.Root = analyzer.parseRecord(domain, spfRecords[0], 0)
+
+	policy.LookupLimitExceeded = policy.LookupCount > spfLookupLimit
+	if policy.LookupLimitExceeded {
+		policy.Findings = append(policy.Findings, fmt.Sprintf("%d DNS lookups exceed the limit of %d (RFC 7208 section 4.6.4)", policy.LookupCount, spfLookupLimit))
+	}
+	policy.VoidLookupLimitExceeded = policy.VoidLookupCount > spfVoidLookupLimit
+	if policy.VoidLookupLimitExceeded {
+		policy.Findings = append(policy.Findings, fmt.Sprintf("%d void DNS lookups exceed the limit of %d (RFC 7208 section 4.6.4)", policy.VoidLookupCount, spfVoidLookupLimit))
+	}
+
+	policy.AllQualifier = effectiveAll(policy.Root)
+	if policy.AllQualifier == nil {
+		policy.Findings = append(policy.Findings, "no all mechanism; unmatched senders default to neutral")
+	}
+	policy.PermissiveAll = policy.AllQualifier == nil || *policy.AllQualifier == osintscan.SpfQualifierPass || *policy.AllQualifier == osintscan.SpfQualifierNeutral
+	if policy.AllQualifier != nil && *policy.AllQualifier == osintscan.SpfQualifierPass {
+		policy.Findings = append(policy.Findings, "+all authorizes every host on the internet to send mail")
+	}
+	if policy.AllQualifier != nil && *policy.AllQualifier == osintscan.SpfQualifierNeutral {
+		policy.Findings = append(policy.Findings, "?all makes no assertion about unauthorized senders")
+	}
+
+	return policy
+}
+
+// effectiveAll returns the qualifier of the all mechanism that terminates the evaluation of the record, following the
+// redirect modifier when the record has no all mechanism of its own.
+func effectiveAll(record *osintscan.SpfRecord) *osintscan.SpfQualifier {
+	if record == nil {
+		return nil
+	}
+	for _, mechanism := range record.Mechanisms {
+		if mechanism.Name == "all" {
+			return mechanism.Qualifier.Ptr()
+		}
+	}
+	if record.Redirect != nil {
+		return effectiveAll(record.Redirect.Include)
+	}
+	return nil
+}
+
+func (a *spfAnalyzer) parseRecord(domain string, txt string, depth int) *osintscan.SpfRecord {
+	record := &osintscan.SpfRecord{
+		Domain: domain,
+		Record: &txt,
+	}
+
+	key := strings.ToLower(dns.Fqdn(domain))
+	if a.visiting[key] {
+		record.Errors = append(record.Errors, fmt.Sprintf("include loop detected at %s", domain))
+		return record
+	}
+	a.visiting[key] = true
+	defer delete(a.visiting, key)
+
+	hasAll := false
+	var redirect *osintscan.SpfMechanism
+	for _, term := range strings.Fields(txt)[1:] {
+		if name, value, ok := spfModifier(term); ok {
+			switch name {
+			case "redirect":
+				a.policy.LookupCount++
+				redirect = &osintscan.SpfMechanism{
+					Qualifier:    osintscan.SpfQualifierPass,
+					Name:         name,
+					Value:        &value,
+					CountsLookup: true,
+				}
+			case "exp":
+				// The explanation is only fetched for failing mail and does not count toward the lookup limit
+			default:
+				record.Errors = append(record.Errors, fmt.Sprintf("unknown modifier %q ignored", term))
+			}
+			continue
+		}
+
+		mechanism := a.parseMechanism(domain, term, depth, record)
+		if mechanism.Name == "all" {
+			hasAll = true
+		}
+		record.Mechanisms = append(record.Mechanisms, mechanism)
+	}
+
+	if redirect != nil {
+		record.Redirect = redirect
+		if hasAll {
+			record.Errors = append(record.Errors, "redirect modifier is ignored because the record has an all mechanism")
+		} else {
+			redirect.Include, redirect.VoidLookup = a.expand(*redirect.Value, depth)
+			if redirect.VoidLookup {
+				a.policy.VoidLookupCount++
+			}
+		}
+	}
+	return record
+}
+
+func (a *spfAnalyzer) parseMechanism(domain string, term string, depth int, record *osintscan.SpfRecord) *osintscan.SpfMechanism {
+	qualifier := osintscan.SpfQualifierPass
+	if q, ok := spfQualifiers[term[0]]; ok {
+		qualifier = q
+		term = term[1:]
+	}
+
+	name, value := term, ""
+	if i := strings.IndexAny(term, ":/"); i >= 0 {
+		name, value = term[:i], strings.TrimPrefix(term[i:], ":")
+	}
+	name = strings.ToLower(name)
+
+	mechanism := &osintscan.SpfMechanism{
+		Qualifier: qualifier,
+		Name:      name,
+	}
+	if value != "" {
+		mechanism.Value = &value
+	}
+
+	switch name {
+	case "all":
+		if depth > 0 && qualifier == osintscan.SpfQualifierPass {
+			a.policy.Findings = append(a.policy.Findings, fmt.Sprintf("included record for %s ends in +all", domain))
+		}
+	case "ip4", "ip6":
+		a.addRange(name, value)
+	case "include":
+		mechanism.CountsLookup = true
+		a.policy.LookupCount++
+		mechanism.Include, mechanism.VoidLookup = a.expand(value, depth)
+	case "a", "mx":
+		mechanism.CountsLookup = true
+		a.policy.LookupCount++
+		mechanism.VoidLookup = a.resolveHosts(name, domain, value)
+	case "exists":
+		mechanism.CountsLookup = true
+		a.policy.LookupCount++
+		if !strings.Contains(value, "%") {
+			if addresses, _ := a.lookup(value, dns.TypeA); len(addresses) == 0 {
+				mechanism.VoidLookup = true
+			}
+		}
+	case "ptr":
+		mechanism.CountsLookup = true
+		a.policy.LookupCount++
+		a.policy.Findings = append(a.policy.Findings, fmt.Sprintf("ptr mechanism in %s is deprecated (RFC 7208 section 5.5)", domain))
+	default:
+		record.Errors = append(record.Errors, fmt.Sprintf("unknown mechanism %q", term))
+	}
+
+	if mechanism.VoidLookup {
+		a.policy.VoidLookupCount++
+	}
+	return mechanism
+}
+
+// expand fetches and parses the SPF record of an include or redirect target. The second return value reports whether
+// the lookup was void.
+func (a *spfAnalyzer) expand(target string, depth int) (*osintscan.SpfRecord, bool) {
+	if strings.Contains(target, "%") {
+		return &osintscan.SpfRecord{
+			Domain: target,
+			Errors: []string{"macro targets are only expanded at evaluation time"},
+		}, false
+	}
+	if depth+1 > spfMaxDepth {
+		return &osintscan.SpfRecord{
+			Domain: target,
+			Errors: []string{"maximum include depth reached"},
+		}, false
+	}
+
+	txtRecords, err := a.lookup(target, dns.TypeTXT)
+	if err != nil {
+		return &osintscan.SpfRecord{Domain: target, Errors: []string{err.Error()}}, false
+	}
+
+	var spfRecords []string
+	for _, txt := range txtRecords {
+		if isSPFRecord(txt) {
+			spfRecords = append(spfRecords, txt)
+		}
+	}
+	if len(spfRecords) == 0 {
+		return &osintscan.SpfRecord{
+			Domain: target,
+			Errors: []string{"no SPF record published"},
+		}, len(txtRecords) == 0
+	}
+
+	record := a.parseRecord(target, spfRecords[0], depth+1)
+	if len(spfRecords) > 1 {
+		record.Errors = append(record.Errors, fmt.Sprintf("%d SPF records published", len(spfRecords)))
+	}
+	return record, false
+}
+
+// resolveHosts resolves the hosts referenced by an a or mx mechanism and records their addresses, applying the optional
+// dual CIDR length. It returns true when the lookup was void.
+func (a *spfAnalyzer) resolveHosts(name string, domain string, value string) bool {
+	target, ip4Bits, ip6Bits := parseDualCIDR(value)
+	if target == "" {
+		target = domain
+	}
+	if strings.Contains(target, "%") {
+		return false
+	}
+
+	hosts := []string{target}
+	if name == "mx" {
+		exchanges, _ := a.lookup(target, dns.TypeMX)
+		if len(exchanges) == 0 {
+			return true
+		}
+		hosts = exchanges
+	}
+
+	found := false
+	for _, host := range hosts {
+		for _, questionType := range []uint16{dns.TypeA, dns.TypeAAAA} {
+			addresses, _ := a.lookup(host, questionType)
+			for _, address := range addresses {
+				found = true
+				ip, err := netip.ParseAddr(address)
+				if err != nil {
+					continue
+				}
+				kind, bits := "ip6", ip6Bits
+				if ip.Is4() {
+					kind, bits = "ip4", ip4Bits
+				}
+				if prefix, err := ip.Prefix(bits); err == nil {
+					a.addRange(kind, prefix.String())
+				}
+			}
+		}
+	}
+	return !found && name == "a"
+}
+
+func (a *spfAnalyzer) addRange(kind string, value string) {
+	if value == "" {
+		return
+	}
+	if _, ok := a.ranges[value]; ok {
+		return
+	}
+	a.ranges[value] = struct{}{}
+	if kind == "ip4" {
+		a.policy.Ip4 = append(a.policy.Ip4, value)
+	} else {
+		a.policy.Ip6 = append(a.policy.Ip6, value)
+	}
+}
+
+// lookup returns the presentation values of the answers of the given type.
+func (a *spfAnalyzer) lookup(name string, questionType uint16) ([]string, error) {
+	records, err := queryRecords(a.ctx, a.resolver, name, questionType)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]string, 0, len(records))
+	for _, record := range records {
+		values = append(values, record.Value)
+	}
+	return values, nil
+}
+
+// spfModifier splits a name=value modifier. Mechanisms never contain an equals sign before their first colon or slash.
+func spfModifier(term string) (string, string, bool) {
+	i := strings.Index(term, "=")
+	if i <= 0 || strings.ContainsAny(term[:i], ":/") {
+		return "", "", false
+	}
+	return strings.ToLower(term[:i]), term[i+1:], true
+}
+
+// parseDualCIDR splits the domain-spec of an a or mx mechanism from its optional IPv4 and IPv6 CIDR lengths
+// (e.g. example.com/24//64).
+func parseDualCIDR(value string) (string, int, int) {
+	ip4Bits, ip6Bits := 32, 128
+	target := value
+	if i := strings.Index(value, "//"); i >= 0 {
+		if bits, err := strconv.Atoi(value[i+2:]); err == nil {
+			ip6Bits = bits
+		}
+		target = value[:i]
+	}
+	if i := strings.Index(target, "/"); i >= 0 {
+		if bits, err := strconv.Atoi(target[i+1:]); err == nil {
+			ip4Bits = bits
+		}
+		target = target[:i]
+	}
+	return target, ip4Bits, ip6Bits
+}
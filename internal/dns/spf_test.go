@@ -0,0 +1,139 @@
+package dns
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+
+	osintscan "github.com/Method-Security/osintscan/generated/go"
+)
+
+func TestAnalyzeSPF(t *testing.T) {
+	records := []string{
+		`_spf.example.com. 300 IN TXT "v=spf1 include:_inner.example.com ip4:198.51.100.0/24 -all"`,
+		`_inner.example.com. 300 IN TXT "v=spf1 exists:%{i}._spf.example.com -all"`,
+		`_redirect.example.com. 300 IN TXT "v=spf1 ip4:203.0.113.0/24 -all"`,
+		`_notspf.example.com. 300 IN TXT "google-site-verification=abc"`,
+		`example.com. 300 IN MX 10 mx1.example.com.`,
+		`mx1.example.com. 300 IN A 192.0.2.10`,
+		`mail.example.com. 300 IN A 192.0.2.20`,
+		`mail.example.com. 300 IN AAAA 2001:db8::20`,
+	}
+	manyIncludes := "v=spf1"
+	for i := 1; i <= 11; i++ {
+		records = append(records, fmt.Sprintf(`_n%d.example.com. 300 IN TXT "v=spf1 -all"`, i))
+		manyIncludes += fmt.Sprintf(" include:_n%d.example.com", i)
+	}
+	resolver := startZoneServer(t, records...)
+
+	tests := []struct {
+		name           string
+		txt            []string
+		lookups        int
+		voids          int
+		lookupExceeded bool
+		voidExceeded   bool
+		duplicates     bool
+		all            osintscan.SpfQualifier
+		permissive     bool
+		ip4            []string
+		ip6            []string
+	}{
+		{
+			name: "no record",
+			txt:  []string{"google-site-verification=abc"},
+		},
+		{
+			name: "ranges only",
+			txt:  []string{"v=spf1 ip4:192.0.2.0/24 ip6:2001:db8::/32 -all"},
+			all:  osintscan.SpfQualifierFail,
+			ip4:  []string{"192.0.2.0/24"},
+			ip6:  []string{"2001:db8::/32"},
+		},
+		{
+			name:    "counts nested includes, mx, a and exists",
+			txt:     []string{"v=spf1 include:_spf.example.com mx a:mail.example.com/24//64 ~all"},
+			lookups: 5,
+			all:     osintscan.SpfQualifierSoftfail,
+			ip4:     []string{"198.51.100.0/24", "192.0.2.10/32", "192.0.2.0/24"},
+			ip6:     []string{"2001:db8::/64"},
+		},
+		{
+			name:           "more than ten lookups",
+			txt:            []string{manyIncludes + " -all"},
+			lookups:        11,
+			lookupExceeded: true,
+			all:            osintscan.SpfQualifierFail,
+		},
+		{
+			name:         "void include, a and mx lookups",
+			txt:          []string{"v=spf1 include:missing1.example.com a:missing2.example.com mx:missing3.example.com -all"},
+			lookups:      3,
+			voids:        3,
+			voidExceeded: true,
+			all:          osintscan.SpfQualifierFail,
+		},
+		{
+			name:    "include without an SPF record is not void",
+			txt:     []string{"v=spf1 include:_notspf.example.com -all"},
+			lookups: 1,
+			all:     osintscan.SpfQualifierFail,
+		},
+		{
+			name:    "redirect supplies the all mechanism",
+			txt:     []string{"v=spf1 redirect=_redirect.example.com"},
+			lookups: 1,
+			all:     osintscan.SpfQualifierFail,
+			ip4:     []string{"203.0.113.0/24"},
+		},
+		{
+			name:       "void redirect leaves no all mechanism",
+			txt:        []string{"v=spf1 redirect=missing.example.com"},
+			lookups:    1,
+			voids:      1,
+			permissive: true,
+		},
+		{
+			name:       "duplicate records",
+			txt:        []string{"v=spf1 -all", "v=spf1 +all"},
+			duplicates: true,
+			all:        osintscan.SpfQualifierFail,
+		},
+		{
+			name:       "pass all",
+			txt:        []string{"v=spf1 +all"},
+			all:        osintscan.SpfQualifierPass,
+			permissive: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var txtRecords []*osintscan.DnsRecord
+			for _, txt := range test.txt {
+				txtRecords = append(txtRecords, &osintscan.DnsRecord{Name: "example.com", Value: txt})
+			}
+			policy := analyzeSPF(context.Background(), resolver, "example.com", txtRecords)
+
+			if policy.LookupCount != test.lookups || policy.VoidLookupCount != test.voids {
+				t.Errorf("got %d lookups and %d void lookups, want %d and %d", policy.LookupCount, policy.VoidLookupCount, test.lookups, test.voids)
+			}
+			if policy.LookupLimitExceeded != test.lookupExceeded || policy.VoidLookupLimitExceeded != test.voidExceeded {
+				t.Errorf("got lookup limit exceeded %t and void lookup limit exceeded %t, want %t and %t", policy.LookupLimitExceeded, policy.VoidLookupLimitExceeded, test.lookupExceeded, test.voidExceeded)
+			}
+			if policy.DuplicateRecords != test.duplicates {
+				t.Errorf("got duplicate records %t, want %t", policy.DuplicateRecords, test.duplicates)
+			}
+			var all osintscan.SpfQualifier
+			if policy.AllQualifier != nil {
+				all = *policy.AllQualifier
+			}
+			if all != test.all || policy.PermissiveAll != test.permissive {
+				t.Errorf("got all qualifier %q, permissive %t, want %q, permissive %t", all, policy.PermissiveAll, test.all, test.permissive)
+			}
+			if !slices.Equal(policy.Ip4, test.ip4) || !slices.Equal(policy.Ip6, test.ip6) {
+				t.Errorf("got ranges %v %v, want %v %v", policy.Ip4, policy.Ip6, test.ip4, test.ip6)
+			}
+		})
+	}
+}
@@ -3,90 +3,307 @@ package dns
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	"fmt"
 	"io"
-	"net"
+	"math/rand/v2"
+	"os"
+	"slices"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 
 	osintscan "github.com/Method-Security/osintscan/generated/go"
+	"github.com/miekg/dns"
+	"github.com/projectdiscovery/subfinder/v2/pkg/passive"
 	"github.com/projectdiscovery/subfinder/v2/pkg/runner"
 )
 
-// GetDomainSubdomainsPassive queries subfinder for all subdomains for a given domain. It returns a SubdomainsEnumReport struct containing
-// all subdomains and any errors that occurred.
-func GetDomainSubdomainsPassive(ctx context.Context, domain string) (osintscan.DnsSubenumReport, error) {
+// wildcardProbes is the number of random labels resolved under each parent domain to detect a wildcard. Wildcards
+// that rotate through a pool of addresses need several probes to collect the whole answer set.
+const wildcardProbes = 3
+
+// checkpointInterval is how often a brute force run writes its checkpoint while resolving permutations.
+const checkpointInterval = 30 * time.Second
+
+// emptyNonTerminalThreads is the number of names probed at once to tell empty non-terminals apart from names that only
+// hold other record types.
+const emptyNonTerminalThreads = 10
+
+// PassiveConfig selects the subfinder sources queried by passive enumeration and the API keys they are given.
+type PassiveConfig struct {
+	Sources        []string // Sources to query instead of the default sources
+	ExcludeSources []string // Sources not to query
+	All            bool     // Query every source, including slow ones
+	Recursive      bool     // Only query sources that can enumerate subdomains of subdomains
+	ProviderConfig string   // Path to a subfinder provider config file holding the API keys of keyed sources
+	Resolve        bool     // Resolve every subdomain found
+	Threads        int      // Number of queries in flight at once while resolving
+	RateLimit      int      // Maximum queries per second sent to each resolver while resolving, or 0 for no limit
+}
+
+// GetDomainSubdomainsPassive queries subfinder for all subdomains for a given domain. It returns a SubdomainsEnumReport
+// struct containing all subdomains, the sources that reported each of them and any errors that occurred. Names that are
+// not valid subdomains of the domain are dropped. Keyed sources are given the API keys in the provider config, and the
+// keys in <SOURCE>_API_KEY environment variables, which take precedence. When config.Resolve is set every subdomain is
+// resolved with the resolver and marked live, dead or wildcard.
+func GetDomainSubdomainsPassive(ctx context.Context, domain string, config PassiveConfig, resolver *Resolver) (osintscan.DnsSubenumReport, error) {
 	report := osintscan.DnsSubenumReport{
 		Domain:          domain,
 		EnumerationType: osintscan.DnsSubenumTypePassive,
 	}
 	errors := []string{}
 
+	if err := validatePassiveConfig(config); err != nil {
+		return report, err
+	}
+
 	// Get all valid subdomains
-	subdomains, err := getSubdomainsPassive(ctx, domain)
+	sources, err := getSubdomainsPassive(ctx, domain, config)
 	if err != nil {
 		errors = append(errors, err.Error())
 	}
+	sources, dropped := scopeSources(domain, sources)
+	if dropped > 0 {
+		errors = append(errors, fmt.Sprintf("dropped %d names that are not subdomains of %s", dropped, normalizeName(domain)))
+	}
 
-	report.Subdomains = subdomains
+	report.Subdomains = []string{}
+	for subdomain := range sources {
+		report.Subdomains = append(report.Subdomains, subdomain)
+	}
+	sort.Strings(report.Subdomains)
+	for _, subdomain := range report.Subdomains {
+		report.SubdomainDetails = append(report.SubdomainDetails, newSubdomain(domain, subdomain, osintscan.SubdomainMethodPassive))
+	}
+	report.Sources = sources
+	if config.Resolve {
+		errors = append(errors, resolveSubdomains(ctx, &report, config, resolver)...)
+	}
 	report.Errors = errors
 	return report, nil
 
 }
 
-// SubdomainsEnumReport represents the report of all subdomains for a given domain including all non-fatal errors that occurred.
+// validatePassiveConfig returns an error when the config names an unknown source or a provider config that does not
+// exist. subfinder exits the process on an unknown source, so the names are checked beforehand.
+func validatePassiveConfig(config PassiveConfig) error {
+	for _, name := range slices.Concat(config.Sources, config.ExcludeSources) {
+		if _, ok := passive.NameSourceMap[name]; !ok {
+			return fmt.Errorf("unknown passive source %q, expected one of %s", name, strings.Join(PassiveSourceNames(), ", "))
+		}
+	}
+	if config.ProviderConfig != "" {
+		if _, err := os.Stat(config.ProviderConfig); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-func getSubdomainsPassive(ctx context.Context, domain string) ([]string, error) {
+// PassiveSourceNames returns the names of the sources passive enumeration can query, sorted.
+func PassiveSourceNames() []string {
+	var names []string
+	for name := range passive.NameSourceMap {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// getSubdomainsPassive returns the subdomains found by subfinder, each with the sorted names of the sources that
+// reported it.
+func getSubdomainsPassive(ctx context.Context, domain string, config PassiveConfig) (map[string][]string, error) {
 	subfinderOpts := &runner.Options{
 		Threads:            10, // Thread controls the number of threads to use for active enumerations
 		Timeout:            30, // Timeout is the seconds to wait for sources to respond
 		MaxEnumerationTime: 10, // MaxEnumerationTime is the maximum amount of time in mins to wait for enumeration
+		Sources:            config.Sources,
+		ExcludeSources:     config.ExcludeSources,
+		All:                config.All,
+		OnlyRecursive:      config.Recursive,
+		ProviderConfig:     config.ProviderConfig,
+		JSON:               true,
+		CaptureSources:     true,
 	}
 
 	subfinder, err := runner.NewRunner(subfinderOpts)
 	if err != nil {
-		return []string{}, err
+		return map[string][]string{}, err
+	}
+
+	// Keys from the environment replace the ones loaded from the provider config
+	for _, source := range passive.AllSources {
+		if !source.NeedsKey() {
+			continue
+		}
+		if keys := os.Getenv(strings.ToUpper(source.Name()) + "_API_KEY"); keys != "" {
+			source.AddApiKeys(strings.Split(keys, ","))
+		}
 	}
 
 	output := &bytes.Buffer{}
 	// To run subdomain enumeration on a single domain
 	if err = subfinder.EnumerateSingleDomainWithCtx(ctx, domain, []io.Writer{output}); err != nil {
-		return []string{}, err
+		return map[string][]string{}, err
+	}
+
+	// Every line of the output is a JSON object listing a subdomain and the sources that reported it
+	sources := map[string][]string{}
+	decoder := json.NewDecoder(output)
+	for decoder.More() {
+		var result struct {
+			Host    string   `json:"host"`
+			Sources []string `json:"sources"`
+		}
+		if err := decoder.Decode(&result); err != nil {
+			return sources, err
+		}
+		sort.Strings(result.Sources)
+		sources[result.Host] = result.Sources
+	}
+	return sources, nil
+}
+
+// scopeSources normalizes the names found by passive enumeration and drops the ones that are not valid subdomains of
+// the domain, since sources also return names of related domains and names that are not hostnames. Sources reporting
+// names that only differ in case are merged. It returns the names that are left and the number of names dropped.
+func scopeSources(domain string, sources map[string][]string) (map[string][]string, int) {
+	zone := normalizeName(domain)
+	scoped := make(map[string][]string, len(sources))
+	dropped := 0
+	for host, hostSources := range sources {
+		name := normalizeName(host)
+		if !isSubdomain(name, zone) {
+			dropped++
+			continue
+		}
+		for _, source := range hostSources {
+			if !slices.Contains(scoped[name], source) {
+				scoped[name] = append(scoped[name], source)
+			}
+		}
+		sort.Strings(scoped[name])
 	}
+	return scoped, dropped
+}
 
-	// Convert buffer to string and split by new line
-	subdomains := strings.Split(output.String(), "\n")
+// resolveSubdomains resolves the subdomains of the report with a MassResolver and records whether each of them is live,
+// dead or only answered by a wildcard of its parent domain, along with its addresses and CNAME chain. It returns any
+// non-fatal errors that occurred.
+func resolveSubdomains(ctx context.Context, report *osintscan.DnsSubenumReport, config PassiveConfig, resolver *Resolver) []string {
+	errors := []string{}
+	names := report.Subdomains
 
-	// Trim the last empty string if the output ends with a newline
-	if len(subdomains) > 0 && subdomains[len(subdomains)-1] == "" {
-		subdomains = subdomains[:len(subdomains)-1]
+	engine, err := NewMassResolver(resolver, MassResolverConfig{InFlight: config.Threads, RateLimit: config.RateLimit})
+	if err != nil {
+		return append(errors, fmt.Sprintf("subdomains were not resolved: %s", err.Error()))
+	}
+	fingerprints, err := retrieveFingerprints("")
+	if err != nil {
+		errors = append(errors, fmt.Sprintf("dangling CNAMEs will not be matched to services: %s", err.Error()))
+	}
+
+	var parents []string
+	seenParents := map[string]bool{}
+	for _, name := range names {
+		if _, parent, _ := strings.Cut(name, "."); !seenParents[parent] {
+			seenParents[parent] = true
+			parents = append(parents, parent)
+		}
+	}
+	wildcards, dangling := detectWildcards(ctx, parents, engine, fingerprints)
+	report.Wildcards = wildcards
+	report.DanglingCnames = dangling
+	byDomain := wildcardsByDomain(wildcards)
+
+	resolutions := make([]*osintscan.DnsSubdomainResolution, len(names))
+	emit := func(emit func(string) bool) {
+		for _, name := range names {
+			if !emit(name) {
+				return
+			}
+		}
 	}
-	return subdomains, err
+	engine.Resolve(ctx, emit, func(result MassResult) {
+		resolution := &osintscan.DnsSubdomainResolution{
+			Name:       result.Name,
+			Status:     osintscan.DnsResolutionStatusDead,
+			Rcode:      dns.RcodeToString[dns.RcodeServerFailure],
+			Addresses:  result.Addresses,
+			CnameChain: result.Cnames,
+		}
+		if result.Err == nil {
+			resolution.Rcode = dns.RcodeToString[result.Rcode]
+		}
+		switch {
+		case matchesWildcard(result, byDomain):
+			resolution.Status = osintscan.DnsResolutionStatusWildcard
+		case result.Err == nil && result.Rcode == dns.RcodeSuccess && len(result.Addresses) > 0:
+			resolution.Status = osintscan.DnsResolutionStatusLive
+		case isDangling(result):
+			report.DanglingCnames = append(report.DanglingCnames, danglingCname(result.Name, result, fingerprints))
+		}
+		resolutions[result.Index] = resolution
+		report.SubdomainDetails[result.Index].Addresses = result.Addresses
+		report.SubdomainDetails[result.Index].CnameChain = result.Cnames
+	})
+	for _, resolution := range resolutions {
+		if resolution != nil {
+			report.Resolutions = append(report.Resolutions, resolution)
+		}
+	}
+	if ctx.Err() != nil {
+		errors = append(errors, fmt.Sprintf("resolution stopped before every subdomain was resolved: %s", ctx.Err()))
+	}
+	return errors
 }
 
-// GetDomainSubdomainsBrute queries subfinder for all subdomains for a given domain. It returns a SubdomainsEnumReport struct containing
-// all subdomains and any errors that occurred.
-func GetDomainSubdomainsBrute(ctx context.Context, domain string, subdomainList []string, parallelThreads int, recursiveDepth int, timeout int) (osintscan.DnsSubenumReport, error) {
+// GetDomainSubdomainsBrute brute-forces the subdomains of a given domain by resolving every word of the subdomain list
+// under it, and under the subdomains found at each depth up to recursiveDepth. Answers that match the wildcard records
+// detected under a parent are discarded. It returns a SubdomainsEnumReport struct containing all subdomains and any
+// errors that occurred. Candidates are resolved by a MassResolver with parallelThreads queries in flight and at most
+// rateLimit queries per second sent to each resolver. When checkpointPath is set the progress of the run is written to
+// it periodically, and a checkpoint passed as resume continues the run it was written by. Dangling CNAMEs are matched
+// against the takeover fingerprints at fingerprintsPath.
+func GetDomainSubdomainsBrute(ctx context.Context, domain string, subdomainList []string, parallelThreads int, rateLimit int, recursiveDepth int, timeout int, fingerprintsPath string, checkpointPath string, resume *BruteCheckpoint, resolver *Resolver) (osintscan.DnsSubenumReport, error) {
 	report := osintscan.DnsSubenumReport{
 		Domain:          domain,
 		EnumerationType: osintscan.DnsSubenumTypeBrute,
 	}
-	errors := []string{}
 
-	subdomains := getSubdomainsBrute(ctx, domain, subdomainList, parallelThreads, recursiveDepth, timeout)
+	checkpoint := newBruteCheckpoint(domain, subdomainList, recursiveDepth)
+	if resume != nil {
+		if err := resume.matches(domain, subdomainList, recursiveDepth); err != nil {
+			return report, err
+		}
+		checkpoint = resume
+	}
+
+	engine, err := NewMassResolver(resolver, MassResolverConfig{InFlight: parallelThreads, RateLimit: rateLimit})
+	if err != nil {
+		return report, err
+	}
+	errors := []string{}
+	fingerprints, err := retrieveFingerprints(fingerprintsPath)
+	if err != nil {
+		errors = append(errors, fmt.Sprintf("dangling CNAMEs will not be matched to services: %s", err.Error()))
+	}
+	errors = append(errors, getSubdomainsBrute(ctx, subdomainList, timeout, engine, fingerprints, checkpoint, checkpointPath)...)
 
-	report.Subdomains = subdomains
+	report = *checkpoint.Report
+	errors = append(errors, separateEmptyNonTerminals(ctx, &report, resolver)...)
 	report.Errors = errors
 	return report, nil
 
 }
 
-func getSubdomainsBrute(ctx context.Context, domain string, subdomainList []string, parallelThreads int, recursiveDepth int, timeout int) []string {
-	subdomains := []string{}
-	subdomainsSet := make(map[string]struct{}) // To track unique valid subdomains
-	subdomainsMutex := &sync.Mutex{}
-	semaphore := make(chan struct{}, parallelThreads)
-	var wg sync.WaitGroup
+// getSubdomainsBrute runs the enumeration from the state held by the checkpoint and keeps it up to date as names are
+// resolved, so the checkpoint always holds the results so far.
+func getSubdomainsBrute(ctx context.Context, subdomainList []string, timeout int, engine *MassResolver, fingerprints []osintscan.Fingerprint, checkpoint *BruteCheckpoint, checkpointPath string) []string {
+	errors := []string{}
+	reported := reportedNames(checkpoint.Report)
 
 	var cancel context.CancelFunc
 	if timeout != 0 {
@@ -94,69 +311,445 @@ func getSubdomainsBrute(ctx context.Context, domain string, subdomainList []stri
 		defer cancel()
 	}
 
-	resolver := net.Resolver{}
+	lastSave := time.Now()
+	var saveErr error
+	save := func(force bool) {
+		if checkpointPath == "" || (!force && time.Since(lastSave) < checkpointInterval) {
+			return
+		}
+		lastSave = time.Now()
+		saveErr = checkpoint.write(checkpointPath)
+	}
+
+	for !checkpoint.Complete {
+		if !checkpoint.WildcardsDetected {
+			detected, dangling := detectWildcards(ctx, checkpoint.Parents, engine, fingerprints)
+			if ctx.Err() != nil {
+				break
+			}
+			checkpoint.DepthWildcards = detected
+			checkpoint.Report.Wildcards = append(checkpoint.Report.Wildcards, detected...)
+			checkpoint.Report.DanglingCnames = append(checkpoint.Report.DanglingCnames, dangling...)
+			checkpoint.WildcardsDetected = true
+		}
 
-	// First iteration - test all base subdomains
-	basePermutations := generatePermutations([]string{domain}, subdomainList)
-	validBaseSubdomains := testPermutations(ctx, basePermutations, &resolver, semaphore, &wg, subdomainsMutex, subdomainsSet, &subdomains)
+		testPermutations(ctx, checkpoint, subdomainList, engine, wildcardsByDomain(checkpoint.DepthWildcards), fingerprints, reported, save)
+		if ctx.Err() != nil {
+			break
+		}
 
-	// For each subsequent depth, only build on valid subdomains from previous iteration
-	currentDepthSubdomains := validBaseSubdomains
-	for depth := 2; depth <= recursiveDepth; depth++ {
-		if len(currentDepthSubdomains) == 0 {
-			break // No valid subdomains to build on
+		// Known names are recursed into at their depth like the names found there
+		deeperSeeds := false
+		for _, seed := range checkpoint.Seeds {
+			depth := subdomainDepth(checkpoint.Domain, seed)
+			if depth == checkpoint.Depth && !slices.Contains(checkpoint.DepthSubdomains, seed) {
+				checkpoint.DepthSubdomains = append(checkpoint.DepthSubdomains, seed)
+			}
+			deeperSeeds = deeperSeeds || depth > checkpoint.Depth
 		}
 
-		newPermutations := generatePermutations(currentDepthSubdomains, subdomainList)
-		currentDepthSubdomains = testPermutations(ctx, newPermutations, &resolver, semaphore, &wg, subdomainsMutex, subdomainsSet, &subdomains)
+		// Each subsequent depth only builds on the valid subdomains from the previous one
+		if checkpoint.Depth >= checkpoint.MaxDepth || (len(checkpoint.DepthSubdomains) == 0 && !deeperSeeds) {
+			checkpoint.Complete = true
+		} else {
+			checkpoint.Depth++
+			checkpoint.Parents = checkpoint.DepthSubdomains
+			checkpoint.Index = 0
+			checkpoint.WildcardsDetected = false
+			checkpoint.DepthWildcards = nil
+			checkpoint.DepthSubdomains = nil
+		}
+		save(true)
 	}
 
-	return subdomains
+	if !checkpoint.Complete {
+		save(true)
+		stopped := fmt.Sprintf("enumeration stopped at depth %d after %d of %d permutations: %s", checkpoint.Depth, checkpoint.Index, len(checkpoint.Parents)*len(subdomainList), ctx.Err())
+		if checkpointPath != "" && saveErr == nil {
+			stopped += fmt.Sprintf("; it can be resumed from the checkpoint at %s", checkpointPath)
+		}
+		errors = append(errors, stopped)
+	}
+	if saveErr != nil {
+		errors = append(errors, fmt.Sprintf("could not write checkpoint: %s", saveErr.Error()))
+	}
+	return errors
 }
 
-func testPermutations(ctx context.Context, permutations []string, resolver *net.Resolver, semaphore chan struct{}, wg *sync.WaitGroup, subdomainsMutex *sync.Mutex, subdomainsSet map[string]struct{}, subdomains *[]string) []string {
-	var validSubdomains []string
-	validSubdomainsMutex := &sync.Mutex{}
+// detectWildcards resolves random labels under each parent domain. Any address a random label resolves to comes from a
+// wildcard record, so the returned list holds the wildcard answer set of every parent that has one. A wildcard that
+// only holds other record types answers random labels with NOERROR and no addresses, and is kept with an empty answer
+// set so that its answers are not mistaken for names that exist without addresses. A wildcard CNAME is kept with the
+// end of its chain, and is also returned as a dangling CNAME when that end does not resolve.
+func detectWildcards(ctx context.Context, parents []string, engine *MassResolver, fingerprints []osintscan.Fingerprint) ([]*osintscan.DnsWildcard, []*osintscan.DnsDanglingCname) {
+	addresses := make(map[string]map[string]struct{})
+	cnames := make(map[string]string)
+	dangling := make(map[string]*osintscan.DnsDanglingCname)
 
-	for _, testSubdomain := range permutations {
-		wg.Add(1)
+	probes := func(emit func(string) bool) {
+		for _, parent := range parents {
+			for probe := 0; probe < wildcardProbes; probe++ {
+				if !emit(fmt.Sprintf("osintscan-%x.%s", rand.Uint64(), parent)) {
+					return
+				}
+			}
+		}
+	}
+	engine.Resolve(ctx, probes, func(result MassResult) {
+		_, parent, _ := strings.Cut(result.Name, ".")
+		if isDangling(result) {
+			cnames[parent] = result.Cnames[len(result.Cnames)-1]
+			dangling[parent] = danglingCname("*."+parent, result, fingerprints)
+		}
+		if result.Err != nil || result.Rcode != dns.RcodeSuccess {
+			return
+		}
+		if _, exists := addresses[parent]; !exists {
+			addresses[parent] = make(map[string]struct{})
+		}
+		for _, address := range result.Addresses {
+			addresses[parent][address] = struct{}{}
+		}
+		if len(result.Cnames) > 0 {
+			cnames[parent] = result.Cnames[len(result.Cnames)-1]
+		}
+	})
 
-		go func(testSubdomain string) {
-			defer wg.Done()
+	// Sort the wildcards so that the report lists them in a stable order
+	var domains []string
+	for parent := range addresses {
+		domains = append(domains, parent)
+	}
+	for parent := range cnames {
+		if _, exists := addresses[parent]; !exists {
+			domains = append(domains, parent)
+		}
+	}
+	sort.Strings(domains)
+	var wildcards []*osintscan.DnsWildcard
+	var danglingCnames []*osintscan.DnsDanglingCname
+	for _, parent := range domains {
+		wildcard := &osintscan.DnsWildcard{Domain: parent, Addresses: []string{}}
+		for address := range addresses[parent] {
+			wildcard.Addresses = append(wildcard.Addresses, address)
+		}
+		sort.Strings(wildcard.Addresses)
+		if cname, exists := cnames[parent]; exists {
+			wildcard.Cname = &cname
+		}
+		wildcards = append(wildcards, wildcard)
+		if record, exists := dangling[parent]; exists {
+			danglingCnames = append(danglingCnames, record)
+		}
+	}
+	return wildcards, danglingCnames
+}
 
-			select {
-			case semaphore <- struct{}{}:
-				defer func() { <-semaphore }()
-			case <-ctx.Done():
-				return
-			}
+// wildcardsByDomain returns the wildcards keyed by the domain they are under.
+func wildcardsByDomain(wildcards []*osintscan.DnsWildcard) map[string]*osintscan.DnsWildcard {
+	byDomain := make(map[string]*osintscan.DnsWildcard, len(wildcards))
+	for _, wildcard := range wildcards {
+		byDomain[wildcard.Domain] = wildcard
+	}
+	return byDomain
+}
 
-			_, err := resolver.LookupHost(ctx, testSubdomain)
-			if err == nil {
-				subdomainsMutex.Lock()
-				if _, exists := subdomainsSet[testSubdomain]; !exists {
-					subdomainsSet[testSubdomain] = struct{}{}
-					*subdomains = append(*subdomains, testSubdomain)
-				}
-				subdomainsMutex.Unlock()
+// testPermutations resolves the permutations of the checkpoint's depth, starting at its index, and records the names
+// found on the checkpoint. Every name that exists, with or without addresses, is recursed into at the next depth.
+// The index only advances past permutations whose every predecessor has been resolved, since the names are resolved
+// out of order.
+func testPermutations(ctx context.Context, checkpoint *BruteCheckpoint, subdomainList []string, engine *MassResolver, wildcards map[string]*osintscan.DnsWildcard, fingerprints []osintscan.Fingerprint, reported map[string]int, save func(force bool)) {
+	depthSet := make(map[string]struct{})
+	for _, subdomain := range checkpoint.DepthSubdomains {
+		depthSet[subdomain] = struct{}{}
+	}
+	completed := make(map[int]struct{})
+	start := checkpoint.Index
 
-				validSubdomainsMutex.Lock()
-				validSubdomains = append(validSubdomains, testSubdomain)
-				validSubdomainsMutex.Unlock()
+	engine.Resolve(ctx, generatePermutations(checkpoint.Parents, subdomainList, start), func(result MassResult) {
+		if result.Err != nil && ctx.Err() != nil {
+			// Interrupted before the name was resolved, so it is tested again when the run is resumed
+			return
+		}
+		completed[start+result.Index] = struct{}{}
+		for {
+			if _, done := completed[checkpoint.Index]; !done {
+				break
 			}
-		}(testSubdomain)
+			delete(completed, checkpoint.Index)
+			checkpoint.Index++
+		}
+		defer save(false)
+
+		token, _, _ := strings.Cut(result.Name, ".")
+		if !recordResult(checkpoint.Report, reported, result, wildcards, fingerprints, osintscan.SubdomainMethodBrute, token) {
+			return
+		}
+		if _, exists := depthSet[result.Name]; !exists {
+			depthSet[result.Name] = struct{}{}
+			checkpoint.DepthSubdomains = append(checkpoint.DepthSubdomains, result.Name)
+		}
+	})
+}
+
+// recordResult adds a resolved candidate to the report, unless it is a wildcard answer or does not exist. Names answered
+// with NOERROR but no addresses exist without holding any address records and are recorded separately, until
+// separateEmptyNonTerminals moves the ones that hold no records at all to the empty non-terminals. Names whose CNAME chain ends in
+// NXDOMAIN or SERVFAIL are recorded as dangling CNAMEs, since they are candidates for a subdomain takeover. The token is
+// the wordlist or permutation token that produced the name, if any. It returns whether the name exists and can be built
+// on.
+func recordResult(report *osintscan.DnsSubenumReport, reported map[string]int, result MassResult, wildcards map[string]*osintscan.DnsWildcard, fingerprints []osintscan.Fingerprint, method osintscan.SubdomainMethod, token string) bool {
+	if matchesWildcard(result, wildcards) {
+		return false
+	}
+	if isDangling(result) {
+		if _, exists := reported[result.Name]; !exists {
+			reported[result.Name] = -1
+			report.DanglingCnames = append(report.DanglingCnames, danglingCname(result.Name, result, fingerprints))
+		}
+		return false
+	}
+	if result.Err != nil || result.Rcode != dns.RcodeSuccess {
+		return false
+	}
+	index, exists := reported[result.Name]
+	switch {
+	case !exists && len(result.Addresses) > 0:
+		subdomain := newSubdomain(report.Domain, result.Name, method)
+		subdomain.Addresses = result.Addresses
+		subdomain.CnameChain = result.Cnames
+		if token != "" {
+			subdomain.Token = &token
+		}
+		reported[result.Name] = len(report.SubdomainDetails)
+		report.Subdomains = append(report.Subdomains, result.Name)
+		report.SubdomainDetails = append(report.SubdomainDetails, subdomain)
+	case !exists:
+		reported[result.Name] = -1
+		report.NoAddressNames = append(report.NoAddressNames, result.Name)
+	case index >= 0:
+		// A name already found by another method records this one as well
+		if subdomain := report.SubdomainDetails[index]; !slices.Contains(subdomain.Methods, method) {
+			subdomain.Methods = append(subdomain.Methods, method)
+		}
+	}
+	return true
+}
+
+// separateEmptyNonTerminals moves the names of the report that hold no records at all from its NoAddressNames to its
+// EmptyNonTerminals. A name answered with NODATA is either an empty non-terminal, which only exists for the names below
+// it, or a name that only holds other record types, such as MX or TXT, and isEmptyNonTerminal tells the two apart. Names
+// it cannot decide on are left in NoAddressNames. It returns any non-fatal errors that occurred.
+func separateEmptyNonTerminals(ctx context.Context, report *osintscan.DnsSubenumReport, resolver *Resolver) []string {
+	errors := []string{}
+	names := report.NoAddressNames
+	if len(names) == 0 || ctx.Err() != nil {
+		return errors
 	}
 
+	empty := make([]bool, len(names))
+	probeErrors := make([]error, len(names))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for worker := 0; worker < min(emptyNonTerminalThreads, len(names)); worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				empty[i], probeErrors[i] = isEmptyNonTerminal(ctx, resolver, names[i])
+			}
+		}()
+	}
+	for i := range names {
+		jobs <- i
+	}
+	close(jobs)
 	wg.Wait()
-	return validSubdomains
+
+	var noAddressNames []string
+	for i, name := range names {
+		if probeErrors[i] != nil {
+			errors = append(errors, fmt.Sprintf("could not tell whether %s is an empty non-terminal: %s", name, probeErrors[i].Error()))
+		}
+		if empty[i] {
+			report.EmptyNonTerminals = append(report.EmptyNonTerminals, name)
+		} else {
+			noAddressNames = append(noAddressNames, name)
+		}
+	}
+	report.NoAddressNames = noAddressNames
+	return errors
 }
 
-func generatePermutations(validSubdomains []string, subdomainList []string) []string {
-	results := make([]string, 0, len(validSubdomains)*len(subdomainList))
-	for _, subdomain := range subdomainList {
-		for _, validSubdomain := range validSubdomains {
-			results = append(results, subdomain+"."+validSubdomain)
+// isEmptyNonTerminal reports whether the name, which exists without address records, holds no records at all. It asks
+// for every type of the name with the DNSSEC OK bit set. An NSEC record owned by the name lists the types it holds, and
+// an NSEC record covering the name that points below it proves that the name only exists for the names below it.
+// Otherwise an answer without any record of the name proves it empty, while an RFC 8482 minimal answer to the ANY query
+// says nothing about the types the name holds.
+func isEmptyNonTerminal(ctx context.Context, resolver *Resolver, name string) (bool, error) {
+	owner := dns.CanonicalName(name)
+	msg := &dns.Msg{}
+	msg.SetQuestion(owner, dns.TypeANY)
+	msg.SetEdns0(4096, true)
+	resp, err := resolver.Exchange(ctx, msg)
+	if err != nil {
+		return false, err
+	}
+	if resp.Msg.Rcode != dns.RcodeSuccess {
+		return false, nil
+	}
+
+	for _, rr := range resp.Msg.Ns {
+		record, ok := rr.(*dns.NSEC)
+		if !ok {
+			continue
+		}
+		next := dns.CanonicalName(record.NextDomain)
+		if dns.CanonicalName(record.Hdr.Name) == owner {
+			return !slices.ContainsFunc(record.TypeBitMap, func(recordType uint16) bool {
+				return recordType != dns.TypeNSEC && recordType != dns.TypeRRSIG
+			}), nil
+		}
+		if next != owner && dns.IsSubDomain(owner, next) {
+			return true, nil
+		}
+	}
+	for _, rr := range resp.Msg.Answer {
+		if hinfo, ok := rr.(*dns.HINFO); ok && hinfo.Cpu == "RFC8482" {
+			return false, nil
+		}
+		if dns.CanonicalName(rr.Header().Name) == owner {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// isSubdomain reports whether the name is a valid hostname below the zone. Both are expected to be normalized.
+func isSubdomain(name string, zone string) bool {
+	return strings.HasSuffix(name, "."+zone) && !slices.ContainsFunc(strings.Split(name, "."), func(label string) bool { return !validLabel(label) })
+}
+
+// newSubdomain describes the subdomain of the domain, found by the method now.
+func newSubdomain(domain string, name string, method osintscan.SubdomainMethod) *osintscan.Subdomain {
+	_, parent, _ := strings.Cut(name, ".")
+	return &osintscan.Subdomain{
+		Name:      name,
+		Parent:    parent,
+		Depth:     subdomainDepth(domain, name),
+		FirstSeen: time.Now().UTC(),
+		Method:    method,
+		Methods:   []osintscan.SubdomainMethod{method},
+	}
+}
+
+// subdomainDepth returns the number of labels the subdomain has below the domain, which for brute force is the
+// recursion depth it is found at.
+func subdomainDepth(domain string, name string) int {
+	return strings.Count(strings.TrimSuffix(normalizeName(name), normalizeName(domain)), ".")
+}
+
+// reportedNames returns the names already in the report, mapped to the index of their entry in the subdomain details,
+// or to -1 when they have none.
+func reportedNames(report *osintscan.DnsSubenumReport) map[string]int {
+	reported := make(map[string]int)
+	for _, name := range slices.Concat(report.Subdomains, report.NoAddressNames, report.EmptyNonTerminals) {
+		reported[name] = -1
+	}
+	for _, dangling := range report.DanglingCnames {
+		reported[dangling.Name] = -1
+	}
+	for index, subdomain := range report.SubdomainDetails {
+		reported[subdomain.Name] = index
+	}
+	return reported
+}
+
+// matchesWildcard reports whether the answer for the subdomain is one the wildcard under its parent would give, in which
+// case the subdomain cannot be told apart from a name that does not exist. Answers with addresses match when every
+// address is also an answer of the wildcard, and answers without addresses when the wildcard has none either and ends
+// its CNAME chain, if any, at the same target.
+func matchesWildcard(result MassResult, wildcards map[string]*osintscan.DnsWildcard) bool {
+	_, parent, found := strings.Cut(result.Name, ".")
+	if !found {
+		return false
+	}
+	wildcard, exists := wildcards[parent]
+	if !exists {
+		return false
+	}
+	if len(result.Addresses) == 0 {
+		if wildcard.Cname == nil {
+			return len(wildcard.Addresses) == 0 && !isDangling(result)
+		}
+		return len(wildcard.Addresses) == 0 && len(result.Cnames) > 0 && result.Cnames[len(result.Cnames)-1] == *wildcard.Cname
+	}
+	for _, address := range result.Addresses {
+		if !slices.Contains(wildcard.Addresses, address) {
+			return false
+		}
+	}
+	return true
+}
+
+// isDangling reports whether the name is a CNAME whose chain ends in a name that does not exist or cannot be resolved.
+func isDangling(result MassResult) bool {
+	return len(result.Cnames) > 0 && (result.Err != nil || result.Rcode == dns.RcodeNameError)
+}
+
+// danglingCname describes the dangling CNAME, along with the takeover fingerprint of the service its chain points at.
+func danglingCname(name string, result MassResult, fingerprints []osintscan.Fingerprint) *osintscan.DnsDanglingCname {
+	record := &osintscan.DnsDanglingCname{
+		Name:  name,
+		Chain: result.Cnames,
+		Rcode: dns.RcodeToString[dns.RcodeServerFailure],
+	}
+	if result.Err == nil {
+		record.Rcode = dns.RcodeToString[result.Rcode]
+	}
+	if fingerprint := matchFingerprint(result.Cnames, record.Rcode == dns.RcodeToString[dns.RcodeNameError], fingerprints); fingerprint != nil {
+		record.Service = &fingerprint.Service
+		record.Vulnerable = &fingerprint.Vulnerable
+	}
+	return record
+}
+
+// matchFingerprint returns the fingerprint of the service any name in the chain belongs to. When the chain ends in
+// NXDOMAIN, fingerprints that identify the service by an NXDOMAIN target are preferred.
+func matchFingerprint(chain []string, nxDomain bool, fingerprints []osintscan.Fingerprint) *osintscan.Fingerprint {
+	var match *osintscan.Fingerprint
+	for i := range fingerprints {
+		fingerprint := &fingerprints[i]
+		for _, cname := range fingerprint.Cname {
+			cname = strings.ToLower(strings.Trim(cname, "."))
+			if cname == "" || !slices.ContainsFunc(chain, func(target string) bool {
+				target = strings.ToLower(target)
+				return target == cname || strings.HasSuffix(target, "."+cname)
+			}) {
+				continue
+			}
+			if match == nil || (nxDomain && fingerprint.NxDomain && !match.NxDomain) {
+				match = fingerprint
+			}
+		}
+	}
+	return match
+}
+
+// generatePermutations returns a producer of every word of the list under every valid subdomain, skipping the first
+// skip permutations. Candidates are built as the resolver consumes them rather than held in memory all at once.
+func generatePermutations(validSubdomains []string, subdomainList []string, skip int) func(emit func(string) bool) {
+	return func(emit func(string) bool) {
+		if len(validSubdomains) == 0 {
+			return
+		}
+		for i := skip / len(validSubdomains); i < len(subdomainList); i++ {
+			for j := skip % len(validSubdomains); j < len(validSubdomains); j++ {
+				if !emit(subdomainList[i] + "." + validSubdomains[j]) {
+					return
+				}
+			}
+			skip = 0
 		}
 	}
-	return results
 }
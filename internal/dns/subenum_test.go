@@ -0,0 +1,354 @@
+package dns
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	osintscan "github.com/Method-Security/osintscan/generated/go"
+	"github.com/miekg/dns"
+)
+
+// startMassResolver starts a zone server from the records and returns a MassResolver that queries it.
+func startMassResolver(t *testing.T, records ...string) *MassResolver {
+	t.Helper()
+	engine, err := NewMassResolver(startZoneServer(t, records...), MassResolverConfig{InFlight: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return engine
+}
+
+func TestDetectWildcards(t *testing.T) {
+	engine := startMassResolver(t,
+		`*.example.com. 300 IN A 192.0.2.1`,
+		`*.example.com. 300 IN AAAA 2001:db8::1`,
+		`*.cdn.example.com. 300 IN CNAME edge.example.net.`,
+		`edge.example.net. 300 IN A 198.51.100.7`,
+		`*.txt.example.com. 300 IN TXT "wildcard"`,
+		`*.old.example.com. 300 IN CNAME gone.example.net.`,
+		`www.example.org. 300 IN A 203.0.113.1`,
+	)
+	gone := "gone.example.net"
+	edge := "edge.example.net"
+
+	tests := []struct {
+		name      string
+		parent    string
+		wildcard  *osintscan.DnsWildcard
+		dangling  bool
+		noneFound bool
+	}{
+		{name: "address wildcard", parent: "example.com", wildcard: &osintscan.DnsWildcard{Domain: "example.com", Addresses: []string{"192.0.2.1"}}},
+		{name: "CNAME wildcard", parent: "cdn.example.com", wildcard: &osintscan.DnsWildcard{Domain: "cdn.example.com", Addresses: []string{"198.51.100.7"}, Cname: &edge}},
+		{name: "wildcard without addresses", parent: "txt.example.com", wildcard: &osintscan.DnsWildcard{Domain: "txt.example.com", Addresses: []string{}}},
+		{name: "dangling CNAME wildcard", parent: "old.example.com", wildcard: &osintscan.DnsWildcard{Domain: "old.example.com", Addresses: []string{}, Cname: &gone}, dangling: true},
+		{name: "no wildcard", parent: "example.org", noneFound: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			wildcards, dangling := detectWildcards(context.Background(), []string{test.parent}, engine, nil)
+			if test.noneFound {
+				if len(wildcards) != 0 {
+					t.Fatalf("got wildcards %+v, want none", wildcards)
+				}
+				return
+			}
+			if len(wildcards) != 1 {
+				t.Fatalf("got %d wildcards, want 1", len(wildcards))
+			}
+			wildcard := wildcards[0]
+			if wildcard.Domain != test.wildcard.Domain || !slices.Equal(wildcard.Addresses, test.wildcard.Addresses) {
+				t.Errorf("got wildcard %s with addresses %v, want %s with %v", wildcard.Domain, wildcard.Addresses, test.wildcard.Domain, test.wildcard.Addresses)
+			}
+			if (wildcard.Cname == nil) != (test.wildcard.Cname == nil) || (wildcard.Cname != nil && *wildcard.Cname != *test.wildcard.Cname) {
+				t.Errorf("got CNAME %v, want %v", wildcard.Cname, test.wildcard.Cname)
+			}
+			if test.dangling != (len(dangling) == 1) || (test.dangling && dangling[0].Name != "*."+test.parent) {
+				t.Errorf("got dangling CNAMEs %+v, want dangling %t", dangling, test.dangling)
+			}
+		})
+	}
+}
+
+func TestMatchesWildcard(t *testing.T) {
+	edge := "edge.example.net"
+	wildcards := wildcardsByDomain([]*osintscan.DnsWildcard{
+		{Domain: "example.com", Addresses: []string{"192.0.2.1", "192.0.2.2"}},
+		{Domain: "cdn.example.com", Addresses: []string{}, Cname: &edge},
+		{Domain: "txt.example.com", Addresses: []string{}},
+	})
+
+	tests := []struct {
+		name    string
+		result  MassResult
+		matches bool
+	}{
+		{name: "wildcard addresses", result: MassResult{Name: "www.example.com", Addresses: []string{"192.0.2.2"}}, matches: true},
+		{name: "some addresses outside the wildcard", result: MassResult{Name: "www.example.com", Addresses: []string{"192.0.2.1", "203.0.113.1"}}},
+		{name: "other addresses", result: MassResult{Name: "www.example.com", Addresses: []string{"203.0.113.1"}}},
+		{name: "no address under an address wildcard", result: MassResult{Name: "www.example.com"}},
+		{name: "name outside every wildcard", result: MassResult{Name: "www.example.org", Addresses: []string{"192.0.2.1"}}},
+		{name: "name two levels below the wildcard", result: MassResult{Name: "a.www.example.com", Addresses: []string{"192.0.2.1"}}},
+		{name: "CNAME to the wildcard target", result: MassResult{Name: "x.cdn.example.com", Cnames: []string{edge}, Rcode: dns.RcodeNameError}, matches: true},
+		{name: "CNAME to another target", result: MassResult{Name: "x.cdn.example.com", Cnames: []string{"other.example.net"}, Rcode: dns.RcodeNameError}},
+		{name: "no addresses under a wildcard without addresses", result: MassResult{Name: "x.txt.example.com"}, matches: true},
+		{name: "dangling CNAME under a wildcard without addresses", result: MassResult{Name: "x.txt.example.com", Cnames: []string{"gone.example.net"}, Err: errors.New("timeout")}},
+		{name: "addresses under a wildcard without addresses", result: MassResult{Name: "x.txt.example.com", Addresses: []string{"192.0.2.1"}}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if matches := matchesWildcard(test.result, wildcards); matches != test.matches {
+				t.Errorf("got matches %t, want %t", matches, test.matches)
+			}
+		})
+	}
+}
+
+func TestIsEmptyNonTerminal(t *testing.T) {
+	tests := []struct {
+		name   string
+		answer []string
+		ns     []string
+		rcode  int
+		empty  bool
+	}{
+		{name: "no records", empty: true},
+		{name: "other record types", answer: []string{`corp.example.com. 300 IN MX 10 mx.example.com.`}},
+		{name: "RFC 8482 minimal answer", answer: []string{`corp.example.com. 300 IN HINFO "RFC8482" ""`}},
+		{
+			name:   "NSEC covering the name points below it",
+			answer: []string{`corp.example.com. 300 IN HINFO "RFC8482" ""`},
+			ns:     []string{`api.example.com. 300 IN NSEC vpn.corp.example.com. A RRSIG NSEC`},
+			empty:  true,
+		},
+		{
+			name: "NSEC owned by the name lists other types",
+			ns:   []string{`corp.example.com. 300 IN NSEC www.example.com. MX RRSIG NSEC`},
+		},
+		{
+			name:   "NSEC owned by the name lists no types",
+			answer: []string{`corp.example.com. 300 IN HINFO "RFC8482" ""`},
+			ns:     []string{`corp.example.com. 300 IN NSEC \000.corp.example.com. RRSIG NSEC`},
+			empty:  true,
+		},
+		{name: "name does not exist", rcode: dns.RcodeNameError},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			address := serveDNS(t, dns.HandlerFunc(func(w dns.ResponseWriter, req *dns.Msg) {
+				resp := &dns.Msg{}
+				resp.SetReply(req)
+				resp.Rcode = test.rcode
+				for _, record := range test.answer {
+					rr, _ := dns.NewRR(record)
+					resp.Answer = append(resp.Answer, rr)
+				}
+				for _, record := range test.ns {
+					rr, _ := dns.NewRR(record)
+					resp.Ns = append(resp.Ns, rr)
+				}
+				_ = w.WriteMsg(resp)
+			}))
+			resolver, err := NewResolver(ResolverConfig{Servers: []string{address}, Timeout: time.Second, Retries: 1})
+			if err != nil {
+				t.Fatal(err)
+			}
+			empty, err := isEmptyNonTerminal(context.Background(), resolver, "corp.example.com")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if empty != test.empty {
+				t.Errorf("got empty non-terminal %t, want %t", empty, test.empty)
+			}
+		})
+	}
+}
+
+func TestGetDomainSubdomainsBruteRecursion(t *testing.T) {
+	// corp only exists for the name below it, mail only holds an MX record and nope does not exist
+	resolver := startZoneServer(t,
+		`vpn.corp.example.com. 300 IN A 192.0.2.1`,
+		`mail.example.com. 300 IN MX 10 smtp.mail.example.com.`,
+		`smtp.mail.example.com. 300 IN A 192.0.2.2`,
+	)
+	wordlist := []string{"corp", "mail", "nope", "vpn", "smtp"}
+	path := filepath.Join(t.TempDir(), "checkpoint.json")
+
+	report, err := GetDomainSubdomainsBrute(context.Background(), "example.com", wordlist, 4, 0, 2, 0, "", path, nil, resolver)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"smtp.mail.example.com", "vpn.corp.example.com"}; !slices.Equal(sorted(report.Subdomains), want) {
+		t.Errorf("got subdomains %v, want %v", report.Subdomains, want)
+	}
+	if want := []string{"corp.example.com"}; !slices.Equal(report.EmptyNonTerminals, want) {
+		t.Errorf("got empty non-terminals %v, want %v", report.EmptyNonTerminals, want)
+	}
+	if want := []string{"mail.example.com"}; !slices.Equal(report.NoAddressNames, want) {
+		t.Errorf("got names without addresses %v, want %v", report.NoAddressNames, want)
+	}
+
+	// The last depth was tested under every name of the first one that exists, with or without data
+	checkpoint, err := LoadBruteCheckpoint(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"corp.example.com", "mail.example.com"}; !slices.Equal(sorted(checkpoint.Parents), want) {
+		t.Errorf("recursed into %v, want %v", checkpoint.Parents, want)
+	}
+}
+
+func TestDanglingCnames(t *testing.T) {
+	// Names either point elsewhere, have an address, or make resolvers fail when a chain reaches them
+	type entry struct {
+		cname   string
+		address string
+		fail    bool
+	}
+	zone := map[string]entry{
+		"old.example.com.":    {cname: "app.azurewebsites.net."},
+		"broken.example.com.": {cname: "hop.example.net."},
+		"hop.example.net.":    {cname: "app.herokuapp.com."},
+		"app.herokuapp.com.":  {fail: true},
+		"loop.example.com.":   {cname: "loop2.example.com."},
+		"loop2.example.com.":  {cname: "loop.example.com."},
+		"live.example.com.":   {cname: "edge.example.net."},
+		"edge.example.net.":   {address: "192.0.2.1"},
+		"down.example.com.":   {fail: true},
+	}
+	address := serveDNS(t, dns.HandlerFunc(func(w dns.ResponseWriter, req *dns.Msg) {
+		resp := &dns.Msg{}
+		resp.SetReply(req)
+		question := req.Question[0]
+		name := dns.CanonicalName(question.Name)
+		if question.Qtype == dns.TypeCNAME {
+			switch record, exists := zone[name]; {
+			case !exists:
+				resp.Rcode = dns.RcodeNameError
+			case record.fail:
+				resp.Rcode = dns.RcodeServerFailure
+			case record.cname != "":
+				rr, _ := dns.NewRR(name + " 300 IN CNAME " + record.cname)
+				resp.Answer = append(resp.Answer, rr)
+			}
+			_ = w.WriteMsg(resp)
+			return
+		}
+		// Address queries are resolved like a recursive resolver would, failing the whole answer on a failing name
+		for hops := 0; hops < traceMaxCNAMEs; hops++ {
+			record, exists := zone[name]
+			if !exists {
+				resp.Rcode = dns.RcodeNameError
+				break
+			}
+			if record.fail {
+				resp = &dns.Msg{}
+				resp.SetRcode(req, dns.RcodeServerFailure)
+				break
+			}
+			if record.address != "" && question.Qtype == dns.TypeA {
+				rr, _ := dns.NewRR(name + " 300 IN A " + record.address)
+				resp.Answer = append(resp.Answer, rr)
+			}
+			if record.cname == "" {
+				break
+			}
+			rr, _ := dns.NewRR(name + " 300 IN CNAME " + record.cname)
+			resp.Answer = append(resp.Answer, rr)
+			name = record.cname
+		}
+		if len(resp.Answer) >= traceMaxCNAMEs {
+			resp = &dns.Msg{}
+			resp.SetRcode(req, dns.RcodeServerFailure)
+		}
+		_ = w.WriteMsg(resp)
+	}))
+	resolver, err := NewResolver(ResolverConfig{Servers: []string{address}, Timeout: time.Second, Retries: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	engine, err := NewMassResolver(resolver, MassResolverConfig{InFlight: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fingerprints := []osintscan.Fingerprint{
+		{Service: "Microsoft Azure", Cname: []string{"azurewebsites.net"}, NxDomain: true, Vulnerable: true},
+		{Service: "Heroku", Cname: []string{"herokuapp.com"}},
+	}
+
+	tests := []struct {
+		name     string
+		dangling bool
+		chain    []string
+		rcode    string
+		service  string
+	}{
+		{name: "old.example.com", dangling: true, chain: []string{"app.azurewebsites.net"}, rcode: "NXDOMAIN", service: "Microsoft Azure"},
+		{name: "broken.example.com", dangling: true, chain: []string{"hop.example.net", "app.herokuapp.com"}, rcode: "SERVFAIL", service: "Heroku"},
+		{name: "live.example.com"},
+		{name: "down.example.com"},
+		{name: "missing.example.com"},
+	}
+	var names []string
+	for _, test := range tests {
+		names = append(names, test.name)
+	}
+	results := resolveAll(engine, names...)
+	for i, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := results[i]
+			if isDangling(result) != test.dangling {
+				t.Fatalf("got dangling %t with chain %v and error %v, want %t", isDangling(result), result.Cnames, result.Err, test.dangling)
+			}
+			if !test.dangling {
+				return
+			}
+			record := danglingCname(result.Name, result, fingerprints)
+			if !slices.Equal(record.Chain, test.chain) || record.Rcode != test.rcode {
+				t.Errorf("got chain %v ending in %s, want %v ending in %s", record.Chain, record.Rcode, test.chain, test.rcode)
+			}
+			if record.Service == nil || *record.Service != test.service {
+				t.Errorf("got service %v, want %s", record.Service, test.service)
+			}
+		})
+	}
+
+	t.Run("CNAME loops stop at the chain limit", func(t *testing.T) {
+		result := resolveAll(engine, "loop.example.com")[0]
+		if !isDangling(result) || len(result.Cnames) != traceMaxCNAMEs {
+			t.Errorf("got chain %v and error %v, want a dangling chain of %d names", result.Cnames, result.Err, traceMaxCNAMEs)
+		}
+	})
+}
+
+func TestScopeSources(t *testing.T) {
+	sources := map[string][]string{
+		"www.example.com":       {"crtsh"},
+		"WWW.example.com.":      {"anubis", "crtsh"},
+		"api.dev.example.com":   {"virustotal"},
+		"example.com":           {"crtsh"},
+		"www.example.com.evil":  {"crtsh"},
+		"notexample.com":        {"crtsh"},
+		"bad_host!.example.com": {"crtsh"},
+	}
+	scoped, dropped := scopeSources("Example.com.", sources)
+	want := map[string][]string{
+		"www.example.com":     {"anubis", "crtsh"},
+		"api.dev.example.com": {"virustotal"},
+	}
+	if len(scoped) != len(want) {
+		t.Errorf("got names %v, want %v", scoped, want)
+	}
+	for name, wantSources := range want {
+		if !slices.Equal(scoped[name], wantSources) {
+			t.Errorf("got sources %v for %s, want %v", scoped[name], name, wantSources)
+		}
+	}
+	if dropped != 4 {
+		t.Errorf("dropped %d names, want 4", dropped)
+	}
+}
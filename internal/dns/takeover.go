@@ -1,6 +1,7 @@
 package dns
 
 import (
+	"context"
 	"crypto/tls"
 	"encoding/json"
 	"errors"
@@ -14,10 +15,11 @@ import (
 	"strings"
 	"time"
 
+	"github.com/Method-Security/osintscan/configs"
 	osintscan "github.com/Method-Security/osintscan/generated/go"
 )
 
-func DetectDomainTakeover(targets []string, fingerprintsPath string, onlySuccessful bool, setHTTPS bool, timeout int) (*osintscan.DomainTakeoverReport, error) {
+func DetectDomainTakeover(ctx context.Context, targets []string, fingerprintsPath string, onlySuccessful bool, setHTTPS bool, timeout int, resolver *Resolver) (*osintscan.DomainTakeoverReport, error) {
 	resources := osintscan.DomainTakeoverReport{}
 	errs := []string{}
 
@@ -43,7 +45,7 @@ func DetectDomainTakeover(targets []string, fingerprintsPath string, onlySuccess
 		}
 
 		for _, url := range urlTargets {
-			domain, cname, err := retrieveCNAMERecord(url)
+			domain, cname, err := retrieveCNAMERecord(ctx, resolver, url)
 			if err != nil {
 				errs = append(errs, err.Error())
 				continue
@@ -83,20 +85,25 @@ func createHTTPClient(setHTTPS bool, timeout int) *http.Client {
 	}
 }
 
+// retrieveFingerprints reads the takeover fingerprints from the file, or returns the bundled fingerprints when no file
+// is given.
 func retrieveFingerprints(fingerprintsPath string) ([]osintscan.Fingerprint, error) {
 	var fingerprints []osintscan.Fingerprint
 
-	absPath, err := filepath.Abs(fingerprintsPath)
-	if err != nil {
-		return nil, err
-	}
+	file := configs.Fingerprints()
+	if fingerprintsPath != "" {
+		absPath, err := filepath.Abs(fingerprintsPath)
+		if err != nil {
+			return nil, err
+		}
 
-	file, err := os.ReadFile(absPath)
-	if err != nil {
-		return nil, err
+		file, err = os.ReadFile(absPath)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	err = json.Unmarshal(file, &fingerprints)
+	err := json.Unmarshal(file, &fingerprints)
 	if err != nil {
 		return nil, errors.New("could not unmarshal fingerprint file")
 	}
@@ -156,13 +163,13 @@ func isVulnerability(body string, fp osintscan.Fingerprint) bool {
 	return false
 }
 
-func retrieveCNAMERecord(url string) (string, string, error) {
+func retrieveCNAMERecord(ctx context.Context, resolver *Resolver, url string) (string, string, error) {
 	domain, err := getDomainFromURL(url)
 	if err != nil {
 		return "", "", err
 	}
 
-	cnameRecord, err := net.LookupCNAME(domain)
+	cnameRecord, err := resolver.LookupCNAME(ctx, domain)
 	if err != nil {
 		return "", "", err
 	}
@@ -0,0 +1,295 @@
+package dns
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+	"time"
+
+	osintscan "github.com/Method-Security/osintscan/generated/go"
+	"github.com/miekg/dns"
+)
+
+const (
+	// traceMaxSteps bounds the number of queries a trace sends so that referral loops terminate
+	traceMaxSteps = 64
+	// traceMaxCNAMEs bounds the length of the CNAME chain a trace follows
+	traceMaxCNAMEs = 10
+)
+
+// rootHints are the IPv4 addresses of the root servers from the IANA root hints file
+// (https://www.internic.net/domain/named.root).
+var rootHints = []traceServer{
+	{"a.root-servers.net", "198.41.0.4"},
+	{"b.root-servers.net", "170.247.170.2"},
+	{"c.root-servers.net", "192.33.4.12"},
+	{"d.root-servers.net", "199.7.91.13"},
+	{"e.root-servers.net", "192.203.230.10"},
+	{"f.root-servers.net", "192.5.5.241"},
+	{"g.root-servers.net", "192.112.36.4"},
+	{"h.root-servers.net", "198.97.190.53"},
+	{"i.root-servers.net", "192.36.148.17"},
+	{"j.root-servers.net", "192.58.128.30"},
+	{"k.root-servers.net", "193.0.14.129"},
+	{"l.root-servers.net", "199.7.83.42"},
+	{"m.root-servers.net", "202.12.27.33"},
+}
+
+// traceServer is a nameserver the trace can send the next query to.
+type traceServer struct {
+	name    string
+	address string
+}
+
+// TraceDomain resolves the name iteratively, starting at the root servers and following every referral down to the
+// authoritative servers of the name, the way dig +trace does. CNAMEs are followed by restarting the trace from the root
+// for the target. Nameservers that are referred to without glue are resolved through the configured resolvers. When
+// rootServers is not empty the trace starts from those servers instead of the root hints. It returns a DnsTraceReport
+// struct with every query of the path, along with any non-fatal errors that occurred.
+func TraceDomain(ctx context.Context, name string, questionType string, rootServers []string, resolver *Resolver) (osintscan.DnsTraceReport, error) {
+	errors := []string{}
+	report := osintscan.DnsTraceReport{
+		Name: strings.TrimSuffix(dns.CanonicalName(name), "."),
+		Type: strings.ToUpper(questionType),
+	}
+	qtype, ok := dns.StringToType[report.Type]
+	if !ok {
+		return report, fmt.Errorf("unknown record type %q", questionType)
+	}
+
+	roots := rootHints
+	if len(rootServers) > 0 {
+		roots = nil
+		for _, server := range rootServers {
+			roots = append(roots, traceServer{name: server, address: server})
+		}
+	}
+
+	start := time.Now()
+	current := dns.CanonicalName(name)
+	zone := "."
+	servers := roots
+	seen := map[string]bool{current: true}
+	for len(report.Steps) < traceMaxSteps {
+		resp, step := traceQuery(ctx, servers, zone, current, qtype, resolver.timeout)
+		report.Steps = append(report.Steps, step...)
+		if resp == nil {
+			errors = append(errors, fmt.Sprintf("no nameserver for %s answered", traceZoneName(zone)))
+			break
+		}
+		last := step[len(step)-1]
+
+		// Answers may hold the target record, a CNAME chain, or both when the chain stays in the same zone
+		if len(resp.Answer) > 0 {
+			target := current
+			for {
+				hop := findCNAME(resp.Answer, target)
+				if hop == nil || qtype == dns.TypeCNAME {
+					break
+				}
+				report.CnameChain = append(report.CnameChain, &osintscan.DnsTraceCnameHop{
+					Name:   strings.TrimSuffix(target, "."),
+					Target: strings.TrimSuffix(dns.CanonicalName(hop.Target), "."),
+					Ttl:    int(hop.Hdr.Ttl),
+				})
+				target = dns.CanonicalName(hop.Target)
+				if seen[target] {
+					errors = append(errors, fmt.Sprintf("CNAME loop at %s", strings.TrimSuffix(target, ".")))
+					report.Rcode = last.Rcode
+					report.TotalTimeMs = int(time.Since(start).Milliseconds())
+					report.Errors = errors
+					return report, nil
+				}
+				seen[target] = true
+			}
+
+			var answers []*osintscan.DnsRecord
+			for _, rr := range resp.Answer {
+				if rr.Header().Rrtype == qtype && dns.CanonicalName(rr.Header().Name) == target {
+					answers = append(answers, dnsRecordFromRR(rr))
+				}
+			}
+			if len(answers) > 0 || target == current {
+				report.Answers = answers
+				report.Rcode = last.Rcode
+				break
+			}
+			if len(report.CnameChain) > traceMaxCNAMEs {
+				errors = append(errors, fmt.Sprintf("CNAME chain is longer than %d hops", traceMaxCNAMEs))
+				break
+			}
+
+			// The target lives in another zone, so it is resolved from the root again
+			current = target
+			zone = "."
+			servers = roots
+			continue
+		}
+
+		referralZone, referral := findReferral(resp, current, zone)
+		if referralZone == "" {
+			// No answer and no referral closer to the name: NXDOMAIN or NODATA from the authoritative servers
+			report.Rcode = last.Rcode
+			break
+		}
+
+		servers = nil
+		for _, nameserver := range referral {
+			addresses := glueAddresses(resp.Extra, nameserver)
+			if len(addresses) == 0 {
+				resolved, err := resolver.LookupHost(ctx, nameserver)
+				if err != nil {
+					errors = append(errors, fmt.Sprintf("could not resolve glueless nameserver %s: %s", strings.TrimSuffix(nameserver, "."), err.Error()))
+					continue
+				}
+				addresses = resolved
+			}
+			for _, address := range addresses {
+				servers = append(servers, traceServer{name: strings.TrimSuffix(nameserver, "."), address: address})
+			}
+		}
+		// IPv6 addresses of every nameserver are only tried after all of the IPv4 ones, so hosts without an IPv6
+		// route do not wait for a timeout on each of them before moving on
+		slices.SortStableFunc(servers, func(a, b traceServer) int {
+			return cmp.Compare(ipv6Address(a.address), ipv6Address(b.address))
+		})
+		zone = referralZone
+	}
+	if len(report.Steps) >= traceMaxSteps {
+		errors = append(errors, fmt.Sprintf("stopped after %d queries without reaching an answer", traceMaxSteps))
+	}
+
+	report.TotalTimeMs = int(time.Since(start).Milliseconds())
+	report.Errors = errors
+	return report, nil
+}
+
+// ipv6Address returns 1 for IPv6 addresses and 0 otherwise, so that servers sort with IPv4 addresses first.
+func ipv6Address(address string) int {
+	if ip := net.ParseIP(address); ip != nil && ip.To4() == nil {
+		return 1
+	}
+	return 0
+}
+
+// traceQuery sends the non-recursive query to the servers of the zone in turn until one answers. Every attempt is
+// recorded as a step, including the ones that failed.
+func traceQuery(ctx context.Context, servers []traceServer, zone string, name string, qtype uint16, timeout time.Duration) (*dns.Msg, []*osintscan.DnsTraceStep) {
+	var steps []*osintscan.DnsTraceStep
+	for _, server := range servers {
+		step := &osintscan.DnsTraceStep{
+			Zone:    traceZoneName(zone),
+			Name:    strings.TrimSuffix(name, "."),
+			Type:    dns.TypeToString[qtype],
+			Server:  server.name,
+			Address: server.address,
+		}
+		steps = append(steps, step)
+
+		upstream, err := parseUpstream(server.address, timeout)
+		if err != nil {
+			message := err.Error()
+			step.Error = &message
+			continue
+		}
+		msg := &dns.Msg{}
+		msg.SetQuestion(name, qtype)
+		msg.RecursionDesired = false
+		msg.SetEdns0(4096, false)
+		resp, rtt, err := upstream.exchange(ctx, msg)
+		step.QueryTimeMs = int(rtt.Milliseconds())
+		if err != nil {
+			message := err.Error()
+			step.Error = &message
+			continue
+		}
+
+		rcode := dns.RcodeToString[resp.Rcode]
+		step.Rcode = &rcode
+		step.Authoritative = resp.Authoritative
+		for _, rr := range resp.Answer {
+			step.Answers = append(step.Answers, dnsRecordFromRR(rr))
+		}
+		if referralZone, referral := findReferral(resp, name, zone); referralZone != "" {
+			referralZoneName := traceZoneName(referralZone)
+			step.ReferralZone = &referralZoneName
+			for _, nameserver := range referral {
+				step.Referral = append(step.Referral, strings.TrimSuffix(nameserver, "."))
+			}
+			for _, rr := range resp.Extra {
+				if rr.Header().Rrtype == dns.TypeA || rr.Header().Rrtype == dns.TypeAAAA {
+					step.Glue = append(step.Glue, dnsRecordFromRR(rr))
+				}
+			}
+		}
+
+		if resp.Rcode == dns.RcodeServerFailure || resp.Rcode == dns.RcodeRefused {
+			continue
+		}
+		return resp, steps
+	}
+	return nil, steps
+}
+
+// findReferral returns the zone and nameservers of a referral in the authority section. Only referrals to a zone below
+// the current one that contains the name count, so that upward referrals cannot send the trace in circles.
+func findReferral(resp *dns.Msg, name string, zone string) (string, []string) {
+	if len(resp.Answer) > 0 {
+		return "", nil
+	}
+	referralZone := ""
+	var nameservers []string
+	for _, rr := range resp.Ns {
+		ns, ok := rr.(*dns.NS)
+		if !ok {
+			continue
+		}
+		owner := dns.CanonicalName(ns.Hdr.Name)
+		if owner == dns.CanonicalName(zone) || !dns.IsSubDomain(zone, owner) || !dns.IsSubDomain(owner, name) {
+			continue
+		}
+		if referralZone != "" && owner != referralZone {
+			continue
+		}
+		referralZone = owner
+		nameservers = append(nameservers, dns.CanonicalName(ns.Ns))
+	}
+	return referralZone, nameservers
+}
+
+func findCNAME(rrs []dns.RR, name string) *dns.CNAME {
+	for _, rr := range rrs {
+		if cname, ok := rr.(*dns.CNAME); ok && dns.CanonicalName(cname.Hdr.Name) == name {
+			return cname
+		}
+	}
+	return nil
+}
+
+// glueAddresses returns the IPv4 glue for the nameserver followed by its IPv6 glue.
+func glueAddresses(extra []dns.RR, nameserver string) []string {
+	var ipv4, ipv6 []string
+	for _, rr := range extra {
+		if dns.CanonicalName(rr.Header().Name) != nameserver {
+			continue
+		}
+		switch record := rr.(type) {
+		case *dns.A:
+			ipv4 = append(ipv4, record.A.String())
+		case *dns.AAAA:
+			ipv6 = append(ipv6, record.AAAA.String())
+		}
+	}
+	return append(ipv4, ipv6...)
+}
+
+// traceZoneName returns the zone without its trailing dot, keeping the root as ".".
+func traceZoneName(zone string) string {
+	if zone == "." {
+		return zone
+	}
+	return strings.TrimSuffix(zone, ".")
+}
@@ -0,0 +1,288 @@
+package dns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"strings"
+
+	osintscan "github.com/Method-Security/osintscan/generated/go"
+	"github.com/miekg/dns"
+)
+
+// errOnlineSigning is returned when a zone answers with minimally covering NSEC records ("black lies" or "white lies").
+// Those records are generated for each query and only cover the queried name, so they never reveal the next name.
+var errOnlineSigning = errors.New("uses online signing with minimally covering NSEC records and cannot be walked")
+
+// nsec3CandidateLimit bounds how many random names are hashed locally while looking for one that falls in a gap of the
+// NSEC3 chain that has not been seen yet.
+const nsec3CandidateLimit = 100000
+
+// GetDomainSubdomainsWalk enumerates the names of a DNSSEC signed zone from its authenticated denial of existence
+// records. Zones signed with NSEC are walked by following each record to the next name in the zone. Zones signed with
+// NSEC3 only publish hashed names, so the hashes are collected for offline cracking instead. Queries are sent to the
+// zone's authoritative nameservers. It returns a DnsSubenumReport struct containing all subdomains and any errors that
+// occurred.
+func GetDomainSubdomainsWalk(ctx context.Context, domain string, maxQueries int, resolver *Resolver) (osintscan.DnsSubenumReport, error) {
+	report := osintscan.DnsSubenumReport{
+		Domain:          domain,
+		EnumerationType: osintscan.DnsSubenumTypeWalk,
+	}
+	errors := []string{}
+	zone := dns.CanonicalName(domain)
+
+	authoritative, err := authoritativeResolver(ctx, resolver, zone)
+	if err != nil {
+		errors = append(errors, fmt.Sprintf("falling back to the configured resolvers: %s", err.Error()))
+		authoritative = resolver
+	}
+
+	// The denial of a name that cannot exist shows which of NSEC and NSEC3 the zone uses
+	probe, err := walkQuery(ctx, authoritative, fmt.Sprintf("osintscan-%x.%s", rand.Uint64(), zone), dns.TypeA)
+	if err != nil {
+		return report, err
+	}
+	for _, rr := range probe.Ns {
+		switch record := rr.(type) {
+		case *dns.NSEC:
+			if minimallyCovering(record) {
+				return report, fmt.Errorf("%s %w", zone, errOnlineSigning)
+			}
+			subdomains, walkErrors := walkNSEC(ctx, authoritative, zone, maxQueries)
+			report.Subdomains = subdomains
+			for _, subdomain := range subdomains {
+				report.SubdomainDetails = append(report.SubdomainDetails, newSubdomain(domain, subdomain, osintscan.SubdomainMethodWalk))
+			}
+			report.Errors = append(errors, walkErrors...)
+			return report, nil
+		case *dns.NSEC3:
+			chain, walkErrors := collectNSEC3(ctx, authoritative, zone, maxQueries, record, probe.Ns)
+			report.Nsec3 = chain
+			errors = append(errors, fmt.Sprintf("zone is signed with NSEC3 and cannot be walked; collected %d hashes for offline cracking", len(chain.Hashes)))
+			report.Errors = append(errors, walkErrors...)
+			return report, nil
+		}
+	}
+	return report, fmt.Errorf("%s returned no NSEC or NSEC3 records; the zone is not signed and cannot be walked", zone)
+}
+
+// authoritativeResolver returns a Resolver that sends queries straight to the zone's nameservers. Walking through a
+// recursive resolver breaks at delegations, where the resolver answers from the child zone instead of the parent.
+func authoritativeResolver(ctx context.Context, resolver *Resolver, zone string) (*Resolver, error) {
+	nsRecords, err := queryRecords(ctx, resolver, zone, dns.TypeNS)
+	if err != nil {
+		return nil, err
+	}
+	var servers []string
+	for _, nsRecord := range nsRecords {
+		addresses, err := resolver.LookupHost(ctx, nsRecord.Value)
+		if err != nil {
+			continue
+		}
+		servers = append(servers, addresses...)
+	}
+	if len(servers) == 0 {
+		return nil, fmt.Errorf("could not resolve any nameserver of %s", zone)
+	}
+	return NewResolver(ResolverConfig{
+		Servers: servers,
+		Timeout: resolver.timeout,
+		Retries: resolver.retries,
+	})
+}
+
+// walkQuery sends a non-recursive query with the DO bit set so that denial of existence records are returned.
+func walkQuery(ctx context.Context, resolver *Resolver, name string, questionType uint16) (*dns.Msg, error) {
+	msg := &dns.Msg{}
+	msg.SetQuestion(dns.Fqdn(name), questionType)
+	msg.RecursionDesired = false
+	msg.SetEdns0(4096, true)
+	resp, err := resolver.Exchange(ctx, msg)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Msg, nil
+}
+
+// walkNSEC follows the NSEC chain of the zone from its apex until it wraps back around.
+func walkNSEC(ctx context.Context, resolver *Resolver, zone string, maxQueries int) ([]string, []string) {
+	subdomains := []string{}
+	var errors []string
+	seen := map[string]bool{zone: true}
+
+	current := zone
+	queries := 0
+	for {
+		if queries >= maxQueries {
+			errors = append(errors, fmt.Sprintf("stopped after %d queries before the NSEC chain wrapped around", queries))
+			break
+		}
+		next, used, err := nextNSEC(ctx, resolver, current)
+		queries += used
+		if err != nil {
+			errors = append(errors, err.Error())
+			break
+		}
+		if seen[next] || !dns.IsSubDomain(zone, next) {
+			break
+		}
+		seen[next] = true
+		subdomains = append(subdomains, strings.TrimSuffix(next, "."))
+		current = next
+	}
+	return subdomains, errors
+}
+
+// nextNSEC returns the name that follows the given name in the zone. The NSEC record owned by the name is requested
+// directly first; servers that do not answer NSEC queries still return it when denying the name immediately after it.
+func nextNSEC(ctx context.Context, resolver *Resolver, name string) (string, int, error) {
+	queries := 0
+	for _, question := range []struct {
+		name         string
+		questionType uint16
+	}{
+		{name, dns.TypeNSEC},
+		{`\000.` + name, dns.TypeA},
+	} {
+		queries++
+		resp, err := walkQuery(ctx, resolver, question.name, question.questionType)
+		if err != nil {
+			return "", queries, err
+		}
+		for _, rr := range append(resp.Answer, resp.Ns...) {
+			record, ok := rr.(*dns.NSEC)
+			if !ok || dns.CanonicalName(record.Hdr.Name) != name {
+				continue
+			}
+			if minimallyCovering(record) {
+				return "", queries, fmt.Errorf("%s %w", name, errOnlineSigning)
+			}
+			return dns.CanonicalName(record.NextDomain), queries, nil
+		}
+	}
+	return "", queries, fmt.Errorf("could not find the NSEC record owned by %s", name)
+}
+
+// minimallyCovering reports whether the NSEC record was synthesized for the query by an online signer. Such records
+// point to the immediate successor of their owner (\000.<owner>), or deny every type but RRSIG and NSEC at a name that
+// does not exist.
+func minimallyCovering(record *dns.NSEC) bool {
+	if strings.HasPrefix(record.NextDomain, `\000.`) {
+		return true
+	}
+	for _, bit := range record.TypeBitMap {
+		if bit != dns.TypeRRSIG && bit != dns.TypeNSEC {
+			return false
+		}
+	}
+	return true
+}
+
+// collectNSEC3 gathers the NSEC3 chain of the zone. Random names are hashed locally with the zone's parameters and only
+// those that fall in a gap of the chain that has not been seen yet are queried, so every query uncovers new hashes.
+func collectNSEC3(ctx context.Context, resolver *Resolver, zone string, maxQueries int, first *dns.NSEC3, initial []dns.RR) (*osintscan.Nsec3Chain, []string) {
+	var errors []string
+	salt := first.Salt
+	if salt == "-" {
+		salt = ""
+	}
+	chain := &osintscan.Nsec3Chain{
+		Zone:          strings.TrimSuffix(zone, "."),
+		HashAlgorithm: int(first.Hash),
+		Iterations:    int(first.Iterations),
+		Salt:          strings.ToLower(salt),
+		OptOut:        first.Flags&1 != 0,
+	}
+	hashes := map[string]*osintscan.Nsec3Hash{}
+	addHashes := func(rrs []dns.RR) {
+		for _, rr := range rrs {
+			record, ok := rr.(*dns.NSEC3)
+			if !ok {
+				continue
+			}
+			owner := strings.ToUpper(dns.SplitDomainName(record.Hdr.Name)[0])
+			if _, found := hashes[owner]; found {
+				continue
+			}
+			hash := &osintscan.Nsec3Hash{Hash: owner, NextHash: strings.ToUpper(record.NextDomain)}
+			for _, bit := range record.TypeBitMap {
+				hash.Types = append(hash.Types, dns.TypeToString[bit])
+			}
+			hashes[owner] = hash
+			chain.Hashes = append(chain.Hashes, hash)
+		}
+	}
+	addHashes(initial)
+
+	for queries := 0; !nsec3ChainComplete(hashes); queries++ {
+		if queries >= maxQueries {
+			errors = append(errors, fmt.Sprintf("stopped after %d queries before the NSEC3 chain was complete", queries))
+			break
+		}
+
+		var name string
+		for i := 0; i < nsec3CandidateLimit; i++ {
+			candidate := fmt.Sprintf("%x.%s", rand.Uint64(), zone)
+			if !nsec3Covered(hashes, dns.HashName(candidate, first.Hash, first.Iterations, first.Salt)) {
+				name = candidate
+				break
+			}
+		}
+		if name == "" {
+			errors = append(errors, "could not find a name hashing into the remaining gaps of the NSEC3 chain")
+			break
+		}
+
+		resp, err := walkQuery(ctx, resolver, name, dns.TypeA)
+		if err != nil {
+			errors = append(errors, err.Error())
+			continue
+		}
+		addHashes(resp.Ns)
+	}
+
+	chain.Complete = nsec3ChainComplete(hashes)
+	return chain, errors
+}
+
+// nsec3Covered reports whether the hash is the owner of, or falls inside the range covered by, a known NSEC3 record.
+// Base32hex preserves the ordering of the underlying bytes, so hashes can be compared as strings.
+func nsec3Covered(hashes map[string]*osintscan.Nsec3Hash, hash string) bool {
+	for owner, record := range hashes {
+		switch {
+		case hash == owner:
+			return true
+		case owner < record.NextHash && owner < hash && hash < record.NextHash:
+			return true
+		case owner >= record.NextHash && (hash > owner || hash < record.NextHash):
+			// The last record of the chain wraps around to the first hash
+			return true
+		}
+	}
+	return false
+}
+
+// nsec3ChainComplete reports whether every known NSEC3 record points to another known record, closing the chain.
+func nsec3ChainComplete(hashes map[string]*osintscan.Nsec3Hash) bool {
+	if len(hashes) == 0 {
+		return false
+	}
+	for _, record := range hashes {
+		if _, found := hashes[record.NextHash]; !found {
+			return false
+		}
+	}
+	return true
+}
+
+// WriteNsec3Hashes writes the collected NSEC3 hashes to a file in the hashcat mode 8300 format
+// (hash:.zone:salt:iterations) so they can be cracked offline.
+func WriteNsec3Hashes(path string, chain *osintscan.Nsec3Chain) error {
+	var lines strings.Builder
+	for _, hash := range chain.Hashes {
+		fmt.Fprintf(&lines, "%s:.%s:%s:%d\n", strings.ToLower(hash.Hash), chain.Zone, chain.Salt, chain.Iterations)
+	}
+	return os.WriteFile(path, []byte(lines.String()), 0644)
+}
@@ -0,0 +1,214 @@
+package dns
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	osintscan "github.com/Method-Security/osintscan/generated/go"
+	"github.com/miekg/dns"
+)
+
+// startNSECServer starts an authoritative server for a zone signed with NSEC, whose records link the names in the
+// given order. Online signing servers answer every NSEC query with a minimally covering record instead.
+func startNSECServer(t *testing.T, names []string, online bool) *Resolver {
+	t.Helper()
+	records := map[string]*dns.NSEC{}
+	for i, name := range names {
+		records[name] = &dns.NSEC{
+			Hdr:        dns.RR_Header{Name: name, Rrtype: dns.TypeNSEC, Class: dns.ClassINET, Ttl: 300},
+			NextDomain: names[(i+1)%len(names)],
+			TypeBitMap: []uint16{dns.TypeA, dns.TypeRRSIG, dns.TypeNSEC},
+		}
+	}
+	address := serveDNS(t, dns.HandlerFunc(func(w dns.ResponseWriter, req *dns.Msg) {
+		resp := &dns.Msg{}
+		resp.SetReply(req)
+		resp.Authoritative = true
+		name := dns.CanonicalName(req.Question[0].Name)
+		switch {
+		case online:
+			resp.Ns = append(resp.Ns, &dns.NSEC{
+				Hdr:        dns.RR_Header{Name: name, Rrtype: dns.TypeNSEC, Class: dns.ClassINET, Ttl: 300},
+				NextDomain: `\000.` + name,
+				TypeBitMap: []uint16{dns.TypeRRSIG, dns.TypeNSEC},
+			})
+		case records[name] != nil && req.Question[0].Qtype == dns.TypeNSEC:
+			resp.Answer = append(resp.Answer, records[name])
+		default:
+			resp.Rcode = dns.RcodeNameError
+		}
+		_ = w.WriteMsg(resp)
+	}))
+	resolver, err := NewResolver(ResolverConfig{Servers: []string{address}, Timeout: time.Second, Retries: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resolver
+}
+
+func TestWalkNSEC(t *testing.T) {
+	chain := []string{"example.com.", "api.example.com.", "mail.example.com.", "www.example.com."}
+	tests := []struct {
+		name       string
+		online     bool
+		maxQueries int
+		subdomains []string
+		errOnline  bool
+		errCount   int
+	}{
+		{name: "follows the chain until it wraps around", maxQueries: 100, subdomains: []string{"api.example.com", "mail.example.com", "www.example.com"}},
+		{name: "stops at the query limit", maxQueries: 2, subdomains: []string{"api.example.com", "mail.example.com"}, errCount: 1},
+		{name: "stops at minimally covering records", online: true, maxQueries: 100, subdomains: []string{}, errOnline: true, errCount: 1},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			resolver := startNSECServer(t, chain, test.online)
+			subdomains, walkErrors := walkNSEC(context.Background(), resolver, "example.com.", test.maxQueries)
+			if !slices.Equal(subdomains, test.subdomains) {
+				t.Errorf("got subdomains %v, want %v", subdomains, test.subdomains)
+			}
+			if len(walkErrors) != test.errCount {
+				t.Fatalf("got errors %v, want %d", walkErrors, test.errCount)
+			}
+			if test.errOnline && !strings.Contains(walkErrors[0], errOnlineSigning.Error()) {
+				t.Errorf("got error %q, want the online signing error", walkErrors[0])
+			}
+		})
+	}
+}
+
+func TestNextNSECOnlineSigning(t *testing.T) {
+	resolver := startNSECServer(t, []string{"example.com."}, true)
+	if _, _, err := nextNSEC(context.Background(), resolver, "example.com."); !errors.Is(err, errOnlineSigning) {
+		t.Errorf("got error %v, want %v", err, errOnlineSigning)
+	}
+}
+
+func TestMinimallyCovering(t *testing.T) {
+	tests := []struct {
+		record  string
+		minimal bool
+	}{
+		{`x.example.com. 300 IN NSEC \000.x.example.com. RRSIG NSEC`, true},
+		{`www.example.com. 300 IN NSEC \000.www.example.com. A RRSIG NSEC`, true},
+		{`x.example.com. 300 IN NSEC x\000.example.com. RRSIG NSEC`, true},
+		{`example.com. 300 IN NSEC www.example.com. A NS SOA RRSIG NSEC DNSKEY`, false},
+		{`api.example.com. 300 IN NSEC www.example.com. A AAAA RRSIG NSEC`, false},
+	}
+	for _, test := range tests {
+		rr, err := dns.NewRR(test.record)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if minimal := minimallyCovering(rr.(*dns.NSEC)); minimal != test.minimal {
+			t.Errorf("%s: got minimally covering %t, want %t", test.record, minimal, test.minimal)
+		}
+	}
+}
+
+func TestNsec3Covered(t *testing.T) {
+	// A chain of three hashes, where the last one wraps around to the first
+	hashes := map[string]*osintscan.Nsec3Hash{
+		"2000": {Hash: "2000", NextHash: "5000"},
+		"5000": {Hash: "5000", NextHash: "8000"},
+		"8000": {Hash: "8000", NextHash: "2000"},
+	}
+	partial := map[string]*osintscan.Nsec3Hash{
+		"5000": {Hash: "5000", NextHash: "8000"},
+	}
+	tests := []struct {
+		name    string
+		hashes  map[string]*osintscan.Nsec3Hash
+		hash    string
+		covered bool
+	}{
+		{name: "owner of a record", hashes: hashes, hash: "5000", covered: true},
+		{name: "inside a range", hashes: hashes, hash: "6000", covered: true},
+		{name: "after the last owner", hashes: hashes, hash: "9000", covered: true},
+		{name: "before the first owner", hashes: hashes, hash: "1000", covered: true},
+		{name: "inside the known range", hashes: partial, hash: "7000", covered: true},
+		{name: "before the known range", hashes: partial, hash: "3000", covered: false},
+		{name: "at the end of the known range", hashes: partial, hash: "8000", covered: false},
+		{name: "no records", hashes: map[string]*osintscan.Nsec3Hash{}, hash: "3000", covered: false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if covered := nsec3Covered(test.hashes, test.hash); covered != test.covered {
+				t.Errorf("got covered %t, want %t", covered, test.covered)
+			}
+		})
+	}
+
+	if !nsec3ChainComplete(hashes) {
+		t.Error("got an incomplete chain, want the three hashes to close it")
+	}
+	if nsec3ChainComplete(partial) {
+		t.Error("got a complete chain, want a single record pointing outside the known hashes to leave it open")
+	}
+}
+
+func TestCollectNSEC3(t *testing.T) {
+	zone := "example.com."
+	const salt = "ABCD"
+	var records []*dns.NSEC3
+	for _, name := range []string{zone, "api." + zone, "mail." + zone, "www." + zone, "vpn." + zone} {
+		records = append(records, &dns.NSEC3{
+			Hdr:        dns.RR_Header{Rrtype: dns.TypeNSEC3, Class: dns.ClassINET, Ttl: 300},
+			Hash:       dns.SHA1,
+			Iterations: 2,
+			SaltLength: 2,
+			Salt:       salt,
+			HashLength: 20,
+			TypeBitMap: []uint16{dns.TypeA, dns.TypeRRSIG},
+			NextDomain: dns.HashName(name, dns.SHA1, 2, salt),
+		})
+	}
+	// Every record is owned by the hash before the one it points to, in hash order
+	sort.Slice(records, func(i, j int) bool { return records[i].NextDomain < records[j].NextDomain })
+	for i, record := range records {
+		record.Hdr.Name = records[(i+len(records)-1)%len(records)].NextDomain + "." + zone
+	}
+
+	address := serveDNS(t, dns.HandlerFunc(func(w dns.ResponseWriter, req *dns.Msg) {
+		resp := &dns.Msg{}
+		resp.SetReply(req)
+		resp.Authoritative = true
+		resp.Rcode = dns.RcodeNameError
+		for _, record := range records {
+			if record.Cover(req.Question[0].Name) || record.Match(req.Question[0].Name) {
+				resp.Ns = append(resp.Ns, record)
+			}
+		}
+		_ = w.WriteMsg(resp)
+	}))
+	resolver, err := NewResolver(ResolverConfig{Servers: []string{address}, Timeout: time.Second, Retries: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		maxQueries int
+		complete   bool
+		hashes     int
+	}{
+		{name: "collects the whole chain", maxQueries: 1000, complete: true, hashes: len(records)},
+		{name: "stops at the query limit", maxQueries: 0, complete: false, hashes: 1},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			chain, walkErrors := collectNSEC3(context.Background(), resolver, zone, test.maxQueries, records[0], []dns.RR{records[0]})
+			if chain.Complete != test.complete || len(chain.Hashes) != test.hashes {
+				t.Errorf("got complete %t with %d hashes and errors %v, want complete %t with %d hashes", chain.Complete, len(chain.Hashes), walkErrors, test.complete, test.hashes)
+			}
+			if chain.Iterations != 2 || chain.Salt != "abcd" || chain.HashAlgorithm != int(dns.SHA1) {
+				t.Errorf("got iterations %d, salt %q and algorithm %d, want 2, abcd and %d", chain.Iterations, chain.Salt, chain.HashAlgorithm, dns.SHA1)
+			}
+		})
+	}
+}
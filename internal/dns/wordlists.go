@@ -0,0 +1,71 @@
+package dns
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/Method-Security/osintscan/configs"
+	osintscan "github.com/Method-Security/osintscan/generated/go"
+)
+
+// GetWordlists lists the subdomain wordlists bundled with osintscan and their sizes. When exportName is set, that
+// wordlist is also written to exportPath, gzip compressed when the path ends in .gz. It returns a DnsWordlistsReport
+// struct containing the wordlists and any non-fatal errors that occurred.
+func GetWordlists(exportName string, exportPath string) (osintscan.DnsWordlistsReport, error) {
+	errors := []string{}
+	report := osintscan.DnsWordlistsReport{}
+
+	for _, name := range configs.WordlistNames() {
+		entries, err := configs.Wordlist(name)
+		if err != nil {
+			errors = append(errors, err.Error())
+			continue
+		}
+		report.Wordlists = append(report.Wordlists, &osintscan.DnsWordlist{Name: name, Entries: len(entries)})
+	}
+
+	if exportName != "" {
+		entries, err := configs.Wordlist(exportName)
+		if err != nil {
+			return report, err
+		}
+		if exportPath == "" {
+			exportPath = "wordlist-" + exportName + ".txt"
+		}
+		if err := writeWordlist(exportPath, entries); err != nil {
+			return report, err
+		}
+		report.ExportedFile = &exportPath
+	}
+
+	report.Errors = errors
+	return report, nil
+}
+
+// writeWordlist writes the entries to the file one per line, gzip compressed when the path ends in .gz.
+func writeWordlist(path string, entries []string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	var writer io.Writer = file
+	var gzipWriter *gzip.Writer
+	if strings.HasSuffix(path, ".gz") {
+		gzipWriter = gzip.NewWriter(file)
+		writer = gzipWriter
+	}
+
+	if _, err := io.WriteString(writer, strings.Join(entries, "\n")+"\n"); err != nil {
+		_ = file.Close()
+		return err
+	}
+	if gzipWriter != nil {
+		if err := gzipWriter.Close(); err != nil {
+			_ = file.Close()
+			return err
+		}
+	}
+	return file.Close()
+}
@@ -2,10 +2,13 @@ package utils
 
 import (
 	"bufio"
+	"compress/gzip"
+	"io"
 	"os"
 	"path/filepath"
 )
 
+// GetEntriesFromFiles returns the lines of every file, in order. Gzip compressed files are decompressed transparently.
 func GetEntriesFromFiles(paths []string) ([]string, error) {
 	entries := []string{}
 	for _, path := range paths {
@@ -17,10 +20,10 @@ func GetEntriesFromFiles(paths []string) ([]string, error) {
 		if err != nil {
 			return nil, err
 		}
-		var lines []string
-		scanner := bufio.NewScanner(file)
-		for scanner.Scan() {
-			lines = append(lines, scanner.Text())
+		lines, err := readLines(file)
+		if err != nil {
+			_ = file.Close()
+			return nil, err
 		}
 		err = file.Close()
 		if err != nil {
@@ -30,3 +33,24 @@ func GetEntriesFromFiles(paths []string) ([]string, error) {
 	}
 	return entries, nil
 }
+
+func readLines(file io.Reader) ([]string, error) {
+	reader := bufio.NewReader(file)
+	var source io.Reader = reader
+	// Gzip streams start with the magic bytes 1f 8b
+	if magic, err := reader.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gzipReader, err := gzip.NewReader(reader)
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = gzipReader.Close() }()
+		source = gzipReader
+	}
+
+	var lines []string
+	scanner := bufio.NewScanner(source)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	return lines, scanner.Err()
+}